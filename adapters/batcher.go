@@ -0,0 +1,146 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// ErrBatcherClosed is returned classifying with a closed batcher
+var ErrBatcherClosed = errors.New("batcher closed")
+
+// classifier of samples in a single forward pass
+type classifier func(samples [][]float64) ([]lib.Prediction, error)
+
+// batchItem awaiting classification
+type batchItem struct {
+	features []float64
+	result   chan batchResult
+}
+
+// batchResult of an item
+type batchResult struct {
+	prediction lib.Prediction
+	err        error
+}
+
+// Batcher coalesces concurrent single sample classifications into batched forward passes
+//
+// A batch is classified once it holds maxBatch samples or maxWait has passed since its first sample
+// arrived, whichever comes first.
+type Batcher struct {
+	classify classifier
+	maxBatch int
+	maxWait  time.Duration
+	items    chan batchItem
+	done     chan struct{}
+	closed   sync.Once
+}
+
+// Classify features of a single sample as part of a batch
+func (b *Batcher) Classify(ctx context.Context, features []float64) (lib.Prediction, error) {
+	item := batchItem{features: features, result: make(chan batchResult, 1)}
+
+	select {
+	case <-b.done:
+		return lib.Prediction{}, ErrBatcherClosed
+	default:
+	}
+
+	select {
+	case b.items <- item:
+	case <-b.done:
+		return lib.Prediction{}, ErrBatcherClosed
+	case <-ctx.Done():
+		return lib.Prediction{}, ctx.Err()
+	}
+
+	select {
+	case result := <-item.result:
+		return result.prediction, result.err
+	case <-ctx.Done():
+		return lib.Prediction{}, ctx.Err()
+	}
+}
+
+// Close batcher, samples already batched are still classified, closing again has no effect
+func (b *Batcher) Close() {
+	b.closed.Do(func() {
+		close(b.done)
+	})
+}
+
+func (b *Batcher) run() {
+	for {
+		var batch []batchItem
+
+		select {
+		case item := <-b.items:
+			batch = append(batch, item)
+		case <-b.done:
+			return
+		}
+
+		timer := time.NewTimer(b.maxWait)
+
+	collect:
+		for len(batch) < b.maxBatch {
+			select {
+			case item := <-b.items:
+				batch = append(batch, item)
+			case <-timer.C:
+				break collect
+			case <-b.done:
+				break collect
+			}
+		}
+
+		timer.Stop()
+
+		go b.dispatch(batch)
+	}
+}
+
+// dispatch batch to classifier and fan predictions out, classifying items individually when the batch
+// fails so an invalid sample only fails its own request
+func (b *Batcher) dispatch(batch []batchItem) {
+	samples := make([][]float64, len(batch))
+	for i, item := range batch {
+		samples[i] = item.features
+	}
+
+	predictions, err := b.classify(samples)
+	if err == nil {
+		for i, item := range batch {
+			item.result <- batchResult{prediction: predictions[i]}
+		}
+		return
+	}
+
+	for _, item := range batch {
+		predictions, err := b.classify([][]float64{item.features})
+		if err != nil {
+			item.result <- batchResult{err: err}
+			continue
+		}
+		item.result <- batchResult{prediction: predictions[0]}
+	}
+}
+
+// NewBatcher classifying batches of up to maxBatch samples waiting at most maxWait to fill them
+func NewBatcher(classify classifier, maxBatch int, maxWait time.Duration) *Batcher {
+	b := &Batcher{
+		classify: classify,
+		maxBatch: maxBatch,
+		maxWait:  maxWait,
+		items:    make(chan batchItem),
+		done:     make(chan struct{}),
+	}
+
+	go b.run()
+
+	return b
+}
@@ -0,0 +1,93 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// recordingClassifier records batch sizes classified
+type recordingClassifier struct {
+	sync.Mutex
+	parameters lib.Parameters
+	batches    []int
+}
+
+func (r *recordingClassifier) classify(samples [][]float64) ([]lib.Prediction, error) {
+	r.Lock()
+	r.batches = append(r.batches, len(samples))
+	r.Unlock()
+	return lib.Classify(r.parameters, samples)
+}
+
+func TestBatcher(t *testing.T) {
+	recorder := &recordingClassifier{parameters: testParameters()}
+	batcher := NewBatcher(recorder.classify, 4, time.Second)
+	defer batcher.Close()
+
+	samples := [][]float64{{2, 2}, {0, 0}, {1, 1}, {-1, 3}}
+	expected, err := lib.Classify(testParameters(), samples)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range samples {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			prediction, err := batcher.Classify(context.Background(), samples[i])
+			assert.NoError(t, err)
+			assert.Equal(t, expected[i], prediction)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []int{4}, recorder.batches)
+}
+
+func TestBatcherMaxWait(t *testing.T) {
+	recorder := &recordingClassifier{parameters: testParameters()}
+	batcher := NewBatcher(recorder.classify, 100, 5*time.Millisecond)
+	defer batcher.Close()
+
+	prediction, err := batcher.Classify(context.Background(), []float64{2, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, prediction.Label)
+	assert.Equal(t, []int{1}, recorder.batches)
+}
+
+func TestBatcherInvalidSample(t *testing.T) {
+	recorder := &recordingClassifier{parameters: testParameters()}
+	batcher := NewBatcher(recorder.classify, 2, time.Second)
+	defer batcher.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := batcher.Classify(context.Background(), []float64{2})
+		assert.EqualError(t, err, "sample 0 has 1 features, expected 2")
+	}()
+	go func() {
+		defer wg.Done()
+		prediction, err := batcher.Classify(context.Background(), []float64{2, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, prediction.Label)
+	}()
+	wg.Wait()
+}
+
+func TestBatcherClosed(t *testing.T) {
+	batcher := NewBatcher((&recordingClassifier{parameters: testParameters()}).classify, 2, time.Second)
+	batcher.Close()
+
+	_, err := batcher.Classify(context.Background(), []float64{2, 2})
+
+	assert.Equal(t, ErrBatcherClosed, err)
+	assert.NotPanics(t, batcher.Close)
+}
@@ -0,0 +1,4 @@
+// Package classifierpb contains the gRPC classification service generated from classifier.proto
+package classifierpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative classifier.proto
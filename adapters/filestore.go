@@ -1,23 +1,372 @@
 package adapters
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+
 	"github.com/gregl83/go-binary-classify-nn/lib"
 )
 
+const (
+	// record keys used in parameters CSV
+	layersKey  = "layers"
+	weightsKey = "W"
+	biasKey    = "b"
+
+	// record keys of batch normalization vectors, matched before biasKey which prefixes betaKey
+	gammaKey    = "gamma"
+	betaKey     = "beta"
+	meanKey     = "mean"
+	varianceKey = "variance"
+)
+
+// historyRecord persisted by CreateHistory
+type historyRecord struct {
+	Config map[string]interface{} `json:"config"`
+	lib.History
+}
+
 // store adapter for persisting and retrieving parameters and data
-type store struct {}
+type store struct {
+	// path of parameters file
+	path string
+}
 
 // CreateParameters creates and writes neural network parameters in CSV format
-func (s *store) CreateParameters(parameters lib.Parameters) {
-	// todo
+//
+// Each record is keyed by its first field: "layers" followed by layer sizes, "W<layer>" for a row of
+// weights and "b<layer>" for a layer bias vector. Batch normalized layers add "gamma<layer>",
+// "beta<layer>", "mean<layer>" and "variance<layer>" vectors. The file is written to a temporary file and
+// renamed so readers never observe a partially written file.
+func (s *store) CreateParameters(parameters lib.Parameters) error {
+	return writeAtomic(s.path, func(w io.Writer) error {
+		return writeParameters(w, parameters)
+	})
 }
 
-// ReadParameters reads neural network parameters from CSV
+// ReadParameters reads neural network parameters from CSV, or a NumPy .npz archive of W1, b1, ... WL, bL arrays
 func (s *store) ReadParameters() (lib.Parameters, error) {
-	// todo
+	if filepath.Ext(s.path) == ".npz" {
+		return readNPZParameters(s.path)
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return lib.Parameters{}, err
+	}
+	defer file.Close()
+
+	return readParameters(file)
+}
+
+// ReadData reads samples from CSV with features followed by a label per record
+//
+// A leading header record is skipped. Data is returned with a column per sample and labels as a row. A NumPy
+// .npz archive is read from its X array of features by samples and Y array of labels.
+func (s *store) ReadData(path string) (mat.Dense, mat.Dense, error) {
+	if filepath.Ext(path) == ".npz" {
+		return readNPZData(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return mat.Dense{}, mat.Dense{}, err
+	}
+	defer file.Close()
+
+	return readData(file)
+}
+
+// CreateHistory writes configuration used for training and training history as JSON
+func (s *store) CreateHistory(path string, config map[string]interface{}, history lib.History) error {
+	return writeJSON(path, historyRecord{Config: config, History: history})
+}
+
+// ReadHistory reads configuration used for training and training history from JSON
+func (s *store) ReadHistory(path string) (map[string]interface{}, lib.History, error) {
+	var record historyRecord
+	err := readJSON(path, &record)
+	return record.Config, record.History, err
+}
+
+// CreateEvaluation writes evaluation as JSON
+func (s *store) CreateEvaluation(path string, evaluation lib.Evaluation) error {
+	return writeJSON(path, evaluation)
+}
+
+// ReadEvaluation reads evaluation from JSON
+func (s *store) ReadEvaluation(path string) (lib.Evaluation, error) {
+	var evaluation lib.Evaluation
+	err := readJSON(path, &evaluation)
+	return evaluation, err
+}
+
+// Path of parameters file
+func (s *store) Path() string {
+	return s.path
+}
+
+// writeAtomic writes to a temporary file renamed to path once write succeeds
+func writeAtomic(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func writeJSON(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func writeParameters(w io.Writer, parameters lib.Parameters) error {
+	writer := csv.NewWriter(w)
+
+	layers := make([]string, len(parameters.Layers))
+	for i, nodes := range parameters.Layers {
+		layers[i] = strconv.Itoa(nodes)
+	}
+	writer.Write(append([]string{layersKey}, layers...))
+
+	for layer := 1; layer < len(parameters.Layers); layer++ {
+		weights := &parameters.Weights[layer]
+		rows, _ := weights.Dims()
+		for i := 0; i < rows; i++ {
+			writer.Write(append([]string{weightsKey + strconv.Itoa(layer)}, formatFloats(weights.RawRowView(i))...))
+		}
+
+		writer.Write(append([]string{biasKey + strconv.Itoa(layer)}, formatFloats(mat.Col(nil, 0, &parameters.Bias[layer]))...))
+
+		if layer < len(parameters.BatchNorms) && parameters.BatchNorms[layer] != nil {
+			batchNorm := parameters.BatchNorms[layer]
+			for _, vector := range []struct {
+				key    string
+				values *mat.Dense
+			}{
+				{gammaKey, &batchNorm.Gamma},
+				{betaKey, &batchNorm.Beta},
+				{meanKey, &batchNorm.RunningMean},
+				{varianceKey, &batchNorm.RunningVariance},
+			} {
+				writer.Write(append([]string{vector.key + strconv.Itoa(layer)}, formatFloats(mat.Col(nil, 0, vector.values))...))
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func readParameters(r io.Reader) (lib.Parameters, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return lib.Parameters{}, err
+	}
+
+	if len(records) == 0 || records[0][0] != layersKey {
+		return lib.Parameters{}, fmt.Errorf("parameters missing %s record", layersKey)
+	}
+
+	layers := make([]int, len(records[0])-1)
+	for i, field := range records[0][1:] {
+		if layers[i], err = strconv.Atoi(field); err != nil {
+			return lib.Parameters{}, err
+		}
+	}
+
+	parameters := lib.NewParameters(layers)
+	weights := make([][]float64, len(layers))
+	bias := make([][]float64, len(layers))
+	normalization := map[string][][]float64{}
+
+	for _, record := range records[1:] {
+		values, err := parseFloats(record[1:])
+		if err != nil {
+			return lib.Parameters{}, err
+		}
+
+		key := record[0]
+		switch {
+		case isNormalizationKey(key):
+			prefix := strings.TrimRight(key, "0123456789")
+			layer, err := parseLayer(key[len(prefix):], layers)
+			if err != nil {
+				return lib.Parameters{}, err
+			}
+			if normalization[prefix] == nil {
+				normalization[prefix] = make([][]float64, len(layers))
+			}
+			normalization[prefix][layer] = values
+		case len(key) > len(weightsKey) && key[:len(weightsKey)] == weightsKey:
+			layer, err := parseLayer(key[len(weightsKey):], layers)
+			if err != nil {
+				return lib.Parameters{}, err
+			}
+			weights[layer] = append(weights[layer], values...)
+		case len(key) > len(biasKey) && key[:len(biasKey)] == biasKey:
+			layer, err := parseLayer(key[len(biasKey):], layers)
+			if err != nil {
+				return lib.Parameters{}, err
+			}
+			bias[layer] = values
+		}
+	}
+
+	for layer := 1; layer < len(layers); layer++ {
+		nodes, features := layers[layer], layers[layer-1]
+		if len(weights[layer]) != nodes*features || len(bias[layer]) != nodes {
+			return lib.Parameters{}, fmt.Errorf("parameters layer %d does not match %d x %d", layer, nodes, features)
+		}
+		parameters.Weights[layer] = *mat.NewDense(nodes, features, weights[layer])
+		parameters.Bias[layer] = *mat.NewDense(nodes, 1, bias[layer])
+	}
+
+	if len(normalization) > 0 {
+		parameters.BatchNorms = make([]*lib.BatchNorm, len(layers))
+	}
+
+	for layer := 1; layer < len(layers) && len(normalization) > 0; layer++ {
+		var present int
+		var vectors []*mat.Dense
+		for _, key := range []string{gammaKey, betaKey, meanKey, varianceKey} {
+			if normalization[key] == nil || normalization[key][layer] == nil {
+				continue
+			}
+			present++
+			if values := normalization[key][layer]; len(values) == layers[layer] {
+				vectors = append(vectors, mat.NewDense(layers[layer], 1, values))
+			}
+		}
+
+		if present == 0 {
+			continue
+		}
+
+		if len(vectors) != 4 {
+			return lib.Parameters{}, fmt.Errorf("parameters layer %d batch normalization requires %s, %s, %s and %s of %d units", layer, gammaKey, betaKey, meanKey, varianceKey, layers[layer])
+		}
+
+		parameters.BatchNorms[layer] = &lib.BatchNorm{Gamma: *vectors[0], Beta: *vectors[1], RunningMean: *vectors[2], RunningVariance: *vectors[3]}
+	}
+
+	return parameters, nil
+}
+
+// isNormalizationKey of a batch normalization vector record
+func isNormalizationKey(key string) bool {
+	prefix := strings.TrimRight(key, "0123456789")
+	return len(prefix) < len(key) && (prefix == gammaKey || prefix == betaKey || prefix == meanKey || prefix == varianceKey)
+}
+
+func readData(r io.Reader) (mat.Dense, mat.Dense, error) {
+	reader := csv.NewReader(r)
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return mat.Dense{}, mat.Dense{}, err
+	}
+
+	if len(records) > 0 {
+		if _, err := parseFloats(records[0]); err != nil {
+			records = records[1:] // header
+		}
+	}
+
+	if len(records) == 0 || len(records[0]) < 2 {
+		return mat.Dense{}, mat.Dense{}, fmt.Errorf("data requires at least one sample with features and label")
+	}
+
+	samples, features := len(records), len(records[0])-1
+	data := mat.NewDense(features, samples, nil)
+	labels := mat.NewDense(1, samples, nil)
+
+	for j, record := range records {
+		values, err := parseFloats(record)
+		if err != nil {
+			return mat.Dense{}, mat.Dense{}, fmt.Errorf("data record %d: %s", j+1, err)
+		}
+		data.SetCol(j, values[:features])
+		labels.Set(0, j, values[features])
+	}
+
+	return *data, *labels, nil
+}
+
+func parseLayer(field string, layers []int) (int, error) {
+	layer, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, err
+	}
+	if layer < 1 || layer >= len(layers) {
+		return 0, fmt.Errorf("parameters layer %d out of range", layer)
+	}
+	return layer, nil
+}
+
+func parseFloats(fields []string) ([]float64, error) {
+	values := make([]float64, len(fields))
+	for i, field := range fields {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func formatFloats(values []float64) []string {
+	fields := make([]string, len(values))
+	for i, value := range values {
+		fields[i] = strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return fields
 }
 
 // NewStore adapter
-func NewStore() *store {
-	return &store{}
-}
\ No newline at end of file
+func NewStore(path string) *store {
+	return &store{path: path}
+}
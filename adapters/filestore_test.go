@@ -0,0 +1,49 @@
+package adapters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+func TestParametersBatchNorm(t *testing.T) {
+	parameters := lib.NewNormalizedParameters([]int{2, 3, 1})
+	parameters.BatchNorms[1].Gamma.Set(0, 0, 1.5)
+	parameters.BatchNorms[1].Beta.Set(1, 0, -0.25)
+	parameters.BatchNorms[1].RunningMean.Set(2, 0, 0.125)
+	parameters.BatchNorms[1].RunningVariance.Set(0, 0, 4)
+
+	var b bytes.Buffer
+	assert.NoError(t, writeParameters(&b, parameters))
+	assert.Contains(t, b.String(), "gamma1,1.5,1,1\nbeta1,0,-0.25,0\nmean1,0,0,0.125\nvariance1,4,1,1\n")
+	assert.NotContains(t, b.String(), "gamma2")
+
+	read, err := readParameters(&b)
+	assert.NoError(t, err)
+	assert.Len(t, read.BatchNorms, 3)
+	assert.Nil(t, read.BatchNorms[2])
+	for _, pair := range [][2]*mat.Dense{
+		{&parameters.BatchNorms[1].Gamma, &read.BatchNorms[1].Gamma},
+		{&parameters.BatchNorms[1].Beta, &read.BatchNorms[1].Beta},
+		{&parameters.BatchNorms[1].RunningMean, &read.BatchNorms[1].RunningMean},
+		{&parameters.BatchNorms[1].RunningVariance, &read.BatchNorms[1].RunningVariance},
+	} {
+		assert.True(t, mat.Equal(pair[0], pair[1]))
+	}
+
+	_, err = readParameters(strings.NewReader("layers,2,1\nW1,1,2\nb1,0\ngamma1,1\n"))
+	assert.EqualError(t, err, "parameters layer 1 batch normalization requires gamma, beta, mean and variance of 1 units")
+}
+
+func TestParametersWithoutBatchNorm(t *testing.T) {
+	read, err := readParameters(strings.NewReader("layers,2,1\nW1,1,2\nb1,0.5\n"))
+
+	assert.NoError(t, err)
+	assert.Nil(t, read.BatchNorms)
+	assert.Equal(t, 0.5, read.Bias[1].At(0, 0))
+}
@@ -0,0 +1,73 @@
+package adapters
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters/classifierpb"
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// grpcService adapter exposing classification over gRPC backed by the model served by Server
+type grpcService struct {
+	classifierpb.UnimplementedClassifierServer
+	server *Server
+}
+
+// Classify a batch of samples
+func (g *grpcService) Classify(ctx context.Context, request *classifierpb.ClassifyRequest) (*classifierpb.ClassifyResponse, error) {
+	return g.classify(request)
+}
+
+// ClassifyStream classifies each request of the stream responding in order
+func (g *grpcService) ClassifyStream(stream classifierpb.Classifier_ClassifyStreamServer) error {
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		response, err := g.classify(request)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (g *grpcService) classify(request *classifierpb.ClassifyRequest) (*classifierpb.ClassifyResponse, error) {
+	samples := make([][]float64, len(request.GetSamples()))
+	for i, sample := range request.GetSamples() {
+		samples[i] = sample.GetFeatures()
+	}
+
+	predictions, err := g.server.classify(samples)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	g.server.metrics.observe(predictions...)
+
+	return &classifierpb.ClassifyResponse{Predictions: toProto(predictions)}, nil
+}
+
+func toProto(predictions []lib.Prediction) []*classifierpb.Prediction {
+	res := make([]*classifierpb.Prediction, len(predictions))
+	for i, prediction := range predictions {
+		res[i] = &classifierpb.Prediction{Score: prediction.Score, Label: int32(prediction.Label)}
+	}
+	return res
+}
+
+// NewGRPCService adapter classifying with the model served by server, sharing its reloads and metrics
+func NewGRPCService(server *Server) classifierpb.ClassifierServer {
+	return &grpcService{server: server}
+}
@@ -0,0 +1,97 @@
+package adapters
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters/classifierpb"
+)
+
+func newTestClient(t *testing.T) (classifierpb.ClassifierClient, func()) {
+	listener := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer()
+	classifierpb.RegisterClassifierServer(server, NewGRPCService(newTestServer(t)))
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+
+	return classifierpb.NewClassifierClient(conn), func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func TestGRPCClassify(t *testing.T) {
+	client, closer := newTestClient(t)
+	defer closer()
+
+	response, err := client.Classify(context.Background(), &classifierpb.ClassifyRequest{
+		Samples: []*classifierpb.Sample{
+			{Features: []float64{2, 2}},
+			{Features: []float64{0, 0}},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Predictions, 2)
+	assert.Equal(t, 0.9525741268224334, response.Predictions[0].Score)
+	assert.Equal(t, int32(1), response.Predictions[0].Label)
+	assert.Equal(t, 0.2689414213699951, response.Predictions[1].Score)
+	assert.Equal(t, int32(0), response.Predictions[1].Label)
+}
+
+func TestGRPCClassifyInvalid(t *testing.T) {
+	client, closer := newTestClient(t)
+	defer closer()
+
+	_, err := client.Classify(context.Background(), &classifierpb.ClassifyRequest{
+		Samples: []*classifierpb.Sample{{Features: []float64{2}}},
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "sample 0 has 1 features, expected 2", status.Convert(err).Message())
+}
+
+func TestGRPCClassifyStream(t *testing.T) {
+	client, closer := newTestClient(t)
+	defer closer()
+
+	stream, err := client.ClassifyStream(context.Background())
+	assert.NoError(t, err)
+
+	for _, features := range [][]float64{{2, 2}, {0, 0}, {1, 1}} {
+		assert.NoError(t, stream.Send(&classifierpb.ClassifyRequest{
+			Samples: []*classifierpb.Sample{{Features: features}},
+		}))
+	}
+	assert.NoError(t, stream.CloseSend())
+
+	var labels []int32
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		labels = append(labels, response.Predictions[0].Label)
+	}
+
+	assert.Equal(t, []int32{1, 0, 1}, labels)
+}
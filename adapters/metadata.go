@@ -0,0 +1,124 @@
+package adapters
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// Metadata describing provenance of parameters, persisted alongside them as a model card
+type Metadata struct {
+	// DatasetHash is the SHA-256 of the training data file
+	DatasetHash string `json:"dataset_hash"`
+	// Features names in the order parameters expect them, empty when training data had no header
+	Features []string `json:"features"`
+	// Hyperparameters used for training
+	Hyperparameters map[string]interface{} `json:"hyperparameters"`
+	// Metrics of the final training epoch, null when not finite
+	Metrics lib.Metrics `json:"metrics"`
+	// Threshold scores are classified positive at, nil when not recorded
+	Threshold *float64 `json:"threshold,omitempty"`
+	// Scaler of features before prediction, nil when features are not scaled or it was not recorded
+	Scaler *Scaler `json:"scaler,omitempty"`
+	// CreatedAt time parameters were trained
+	CreatedAt time.Time `json:"created_at"`
+	// Version of tool that trained parameters
+	Version string `json:"version"`
+	// Notes free-form
+	Notes string `json:"notes"`
+}
+
+// Scaler standardizing each feature as (value - Mean) / Scale before prediction
+type Scaler struct {
+	Mean  []float64 `json:"mean"`
+	Scale []float64 `json:"scale"`
+}
+
+// CheckFeatures verifies a feature header matches recorded feature names and order
+//
+// Inputs without a header, or metadata without recorded features, cannot be checked and are accepted.
+func (m Metadata) CheckFeatures(header []string) error {
+	if len(header) == 0 || len(m.Features) == 0 {
+		return nil
+	}
+
+	if len(header) != len(m.Features) {
+		return fmt.Errorf("input has %d features, model expects %d (%s)", len(header), len(m.Features), strings.Join(m.Features, ","))
+	}
+
+	for i, name := range header {
+		if strings.TrimSpace(name) != m.Features[i] {
+			return fmt.Errorf("input feature %d is %q, model expects %q", i, name, m.Features[i])
+		}
+	}
+
+	return nil
+}
+
+// CreateMetadata writes metadata JSON alongside parameters, replacing any previous metadata atomically
+func (s *store) CreateMetadata(metadata Metadata) error {
+	return writeJSON(s.MetadataPath(), metadata)
+}
+
+// ReadMetadata reads metadata JSON stored alongside parameters
+func (s *store) ReadMetadata() (Metadata, error) {
+	var metadata Metadata
+	err := readJSON(s.MetadataPath(), &metadata)
+	return metadata, err
+}
+
+// MetadataPath of metadata stored alongside parameters, parameters path with a .meta.json extension
+func (s *store) MetadataPath() string {
+	return strings.TrimSuffix(s.path, filepath.Ext(s.path)) + ".meta.json"
+}
+
+// ReadHeader reads the header record of a CSV file, nil when the first record is numeric or path is .npz
+func (s *store) ReadHeader(path string) ([]string, error) {
+	if filepath.Ext(path) == ".npz" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	record, err := csv.NewReader(file).Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := parseFloats(record); err == nil {
+		return nil, nil
+	}
+
+	return record, nil
+}
+
+// Checksum computes the hex encoded SHA-256 of a file
+func (s *store) Checksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
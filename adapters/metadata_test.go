@@ -0,0 +1,70 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreMetadata(t *testing.T) {
+	dir, err := os.MkdirTemp("", "metadata")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := NewStore(filepath.Join(dir, "parameters.csv"))
+	threshold := 0.7
+	metadata := Metadata{
+		DatasetHash:     "abc",
+		Features:        []string{"x1", "x2"},
+		Hyperparameters: map[string]interface{}{"learning_rate": 0.01},
+		Metrics:         map[string]float64{"cost": 0.1},
+		Threshold:       &threshold,
+		Scaler:          &Scaler{Mean: []float64{1, 2}, Scale: []float64{0.5, 4}},
+		CreatedAt:       time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+		Version:         "v1.0.0",
+		Notes:           "baseline",
+	}
+
+	assert.NoError(t, s.CreateMetadata(metadata))
+	read, err := s.ReadMetadata()
+
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, read)
+	assert.Equal(t, filepath.Join(dir, "parameters.meta.json"), s.MetadataPath())
+}
+
+func TestMetadataCheckFeatures(t *testing.T) {
+	metadata := Metadata{Features: []string{"x1", "x2"}}
+
+	assert.NoError(t, metadata.CheckFeatures(nil))
+	assert.NoError(t, metadata.CheckFeatures([]string{"x1", " x2"}))
+	assert.NoError(t, Metadata{}.CheckFeatures([]string{"a"}))
+	assert.EqualError(t, metadata.CheckFeatures([]string{"x2", "x1"}), "input feature 0 is \"x2\", model expects \"x1\"")
+	assert.EqualError(t, metadata.CheckFeatures([]string{"x1"}), "input has 1 features, model expects 2 (x1,x2)")
+}
+
+func TestStoreReadHeaderChecksum(t *testing.T) {
+	dir, err := os.MkdirTemp("", "header")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := NewStore(filepath.Join(dir, "parameters.csv"))
+	withHeader, withoutHeader := filepath.Join(dir, "a.csv"), filepath.Join(dir, "b.csv")
+	assert.NoError(t, os.WriteFile(withHeader, []byte("x1,x2,y\n1,2,0\n"), 0644))
+	assert.NoError(t, os.WriteFile(withoutHeader, []byte("1,2,0\n"), 0644))
+
+	header, err := s.ReadHeader(withHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x1", "x2", "y"}, header)
+
+	header, err = s.ReadHeader(withoutHeader)
+	assert.NoError(t, err)
+	assert.Nil(t, header)
+
+	hash, err := s.Checksum(withoutHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, "6b2a5ff680a694ec985ff7f97884f484653a99f85c41bbe0b7a16afaaefd0346", hash)
+}
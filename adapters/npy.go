@@ -0,0 +1,327 @@
+package adapters
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+const (
+	// npyMagic prefix of NumPy array files
+	npyMagic = "\x93NUMPY"
+
+	// npyMaxElements of an array, larger shapes are rejected before reading values
+	npyMaxElements = 1 << 28
+	// npyChunkSize in bytes of values read at a time so truncated arrays fail without allocating their shape
+	npyChunkSize = 1 << 16
+
+	// keys of NumPy arrays read as data
+	featuresArray = "X"
+	labelsArray   = "Y"
+)
+
+var (
+	npyDescr   = regexp.MustCompile(`'descr'\s*:\s*'([^']*)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+)
+
+// ReadNPY reads a NumPy .npy array of at most 2 dimensions
+//
+// Boolean, integer and floating point arrays of either byte order are converted to float64. A 1-d array of
+// n elements is read as an n x 1 column, the shape the Python reference uses for bias vectors, and a 0-d
+// array as 1 x 1.
+func ReadNPY(r io.Reader) (mat.Dense, error) {
+	prefix := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return mat.Dense{}, err
+	}
+
+	if string(prefix[:len(npyMagic)]) != npyMagic {
+		return mat.Dense{}, fmt.Errorf("npy missing magic string")
+	}
+
+	var headerLength int
+	switch major := prefix[len(npyMagic)]; major {
+	case 1:
+		var length uint16
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return mat.Dense{}, err
+		}
+		headerLength = int(length)
+	case 2, 3:
+		var length uint32
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return mat.Dense{}, err
+		}
+		headerLength = int(length)
+	default:
+		return mat.Dense{}, fmt.Errorf("npy version %d not supported", major)
+	}
+
+	header := make([]byte, headerLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return mat.Dense{}, err
+	}
+
+	descr, fortran, shape, err := parseNPYHeader(string(header))
+	if err != nil {
+		return mat.Dense{}, err
+	}
+
+	rows, cols := 1, 1
+	switch len(shape) {
+	case 0:
+	case 1:
+		rows = shape[0]
+	case 2:
+		rows, cols = shape[0], shape[1]
+	default:
+		return mat.Dense{}, fmt.Errorf("npy array has %d dimensions, at most 2 supported", len(shape))
+	}
+
+	if cols > 0 && rows > npyMaxElements/cols {
+		return mat.Dense{}, fmt.Errorf("npy shape %v exceeds %d elements", shape, npyMaxElements)
+	}
+
+	values, err := readNPYValues(r, descr, rows*cols)
+	if err != nil {
+		return mat.Dense{}, err
+	}
+
+	if rows*cols == 0 {
+		return mat.Dense{}, nil
+	}
+
+	if fortran {
+		var transposed mat.Dense
+		transposed.CloneFrom(mat.NewDense(cols, rows, values).T())
+		return transposed, nil
+	}
+
+	return *mat.NewDense(rows, cols, values), nil
+}
+
+// ReadNPZ reads the arrays of a NumPy .npz archive keyed by name (without the .npy extension)
+func ReadNPZ(path string) (map[string]mat.Dense, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	arrays := map[string]mat.Dense{}
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".npy") {
+			continue
+		}
+
+		entry, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		array, err := ReadNPY(entry)
+		entry.Close()
+		if err != nil {
+			return nil, fmt.Errorf("npz array %s: %s", file.Name, err)
+		}
+
+		arrays[strings.TrimSuffix(file.Name, ".npy")] = array
+	}
+
+	return arrays, nil
+}
+
+// ArraysParameters builds parameters from arrays W1, b1, ... WL, bL as saved by the Python reference
+//
+// Layer sizes are inferred from weight shapes.
+func ArraysParameters(arrays map[string]mat.Dense) (lib.Parameters, error) {
+	weights, ok := arrays[weightsKey+"1"]
+	if !ok {
+		return lib.Parameters{}, fmt.Errorf("arrays missing %s1", weightsKey)
+	}
+
+	_, features := weights.Dims()
+	layers := []int{features}
+
+	for layer := 1; ; layer++ {
+		weights, ok := arrays[weightsKey+strconv.Itoa(layer)]
+		if !ok {
+			break
+		}
+		nodes, _ := weights.Dims()
+		layers = append(layers, nodes)
+	}
+
+	parameters := lib.NewParameters(layers)
+	for layer := 1; layer < len(layers); layer++ {
+		bias, ok := arrays[biasKey+strconv.Itoa(layer)]
+		if !ok {
+			return lib.Parameters{}, fmt.Errorf("arrays missing %s%d", biasKey, layer)
+		}
+		parameters.Weights[layer] = arrays[weightsKey+strconv.Itoa(layer)]
+		parameters.Bias[layer] = bias
+	}
+
+	return parameters, parameters.Validate(0)
+}
+
+func parseNPYHeader(header string) (string, bool, []int, error) {
+	descr, fortran, shape := npyDescr.FindStringSubmatch(header), npyFortran.FindStringSubmatch(header), npyShape.FindStringSubmatch(header)
+	if descr == nil || fortran == nil || shape == nil {
+		return "", false, nil, fmt.Errorf("npy header %q malformed", strings.TrimSpace(header))
+	}
+
+	var dimensions []int
+	for _, field := range strings.Split(shape[1], ",") {
+		field = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(field), "L"))
+		if field == "" {
+			continue
+		}
+		dimension, err := strconv.Atoi(field)
+		if err != nil || dimension < 0 {
+			return "", false, nil, fmt.Errorf("npy shape %q malformed", shape[1])
+		}
+		dimensions = append(dimensions, dimension)
+	}
+
+	return descr[1], fortran[1] == "True", dimensions, nil
+}
+
+func readNPYValues(r io.Reader, descr string, n int) ([]float64, error) {
+	decode, size, err := npyDecoder(descr)
+	if err != nil {
+		return nil, err
+	}
+
+	// values are appended as chunks are read so memory grows with the data present, not the declared shape
+	values := make([]float64, 0, minInt(n, npyChunkSize))
+	chunk := make([]byte, minInt(n, npyChunkSize/size)*size)
+	for len(values) < n {
+		b := chunk[:minInt(n-len(values), len(chunk)/size)*size]
+		if _, err := io.ReadFull(r, b); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("npy array truncated after %d of %d values: %s", len(values), n, err)
+		}
+
+		for i := 0; i < len(b); i += size {
+			values = append(values, decode(b[i:i+size]))
+		}
+	}
+
+	return values, nil
+}
+
+// npyDecoder of a single value of dtype descr and its size in bytes
+func npyDecoder(descr string) (func(b []byte) float64, int, error) {
+	unsupported := fmt.Errorf("npy dtype %q not supported", descr)
+	if len(descr) < 3 || !strings.ContainsRune("<>|=", rune(descr[0])) {
+		return nil, 0, unsupported
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if descr[0] == '>' {
+		order = binary.BigEndian
+	}
+
+	kind := descr[1]
+	size, err := strconv.Atoi(descr[2:])
+	if err != nil {
+		return nil, 0, unsupported
+	}
+
+	switch {
+	case kind == 'f' && size == 8:
+		return func(b []byte) float64 { return math.Float64frombits(order.Uint64(b)) }, size, nil
+	case kind == 'f' && size == 4:
+		return func(b []byte) float64 { return float64(math.Float32frombits(order.Uint32(b))) }, size, nil
+	case kind == 'i' && size == 1:
+		return func(b []byte) float64 { return float64(int8(b[0])) }, size, nil
+	case (kind == 'u' || kind == 'b') && size == 1:
+		return func(b []byte) float64 { return float64(b[0]) }, size, nil
+	case kind == 'i' && (size == 2 || size == 4 || size == 8):
+		return func(b []byte) float64 { return float64(signed(order, b)) }, size, nil
+	case kind == 'u' && (size == 2 || size == 4 || size == 8):
+		return func(b []byte) float64 { return float64(unsigned(order, b)) }, size, nil
+	}
+
+	return nil, 0, unsupported
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func unsigned(order binary.ByteOrder, b []byte) uint64 {
+	switch len(b) {
+	case 2:
+		return uint64(order.Uint16(b))
+	case 4:
+		return uint64(order.Uint32(b))
+	}
+	return order.Uint64(b)
+}
+
+func signed(order binary.ByteOrder, b []byte) int64 {
+	switch len(b) {
+	case 2:
+		return int64(int16(order.Uint16(b)))
+	case 4:
+		return int64(int32(order.Uint32(b)))
+	}
+	return int64(order.Uint64(b))
+}
+
+func readNPZParameters(path string) (lib.Parameters, error) {
+	arrays, err := ReadNPZ(path)
+	if err != nil {
+		return lib.Parameters{}, err
+	}
+	return ArraysParameters(arrays)
+}
+
+func readNPZData(path string) (mat.Dense, mat.Dense, error) {
+	arrays, err := ReadNPZ(path)
+	if err != nil {
+		return mat.Dense{}, mat.Dense{}, err
+	}
+
+	data, ok := arrays[featuresArray]
+	if !ok {
+		return mat.Dense{}, mat.Dense{}, fmt.Errorf("npz data missing %s array", featuresArray)
+	}
+
+	labels, ok := arrays[labelsArray]
+	if !ok {
+		return mat.Dense{}, mat.Dense{}, fmt.Errorf("npz data missing %s array", labelsArray)
+	}
+
+	// labels saved as a 1-d array are read as a column
+	if rows, cols := labels.Dims(); cols == 1 && rows > 1 {
+		var row mat.Dense
+		row.CloneFrom(labels.T())
+		labels = row
+	}
+
+	_, samples := data.Dims()
+	if _, cols := labels.Dims(); cols != samples {
+		return mat.Dense{}, mat.Dense{}, fmt.Errorf("npz data has %d samples and %d labels", samples, cols)
+	}
+
+	return data, labels, nil
+}
@@ -0,0 +1,157 @@
+package adapters
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+// npy encodes values as a version 1.0 .npy array as numpy.save does
+func npy(descr, fortran, shape string, order binary.ByteOrder, values interface{}) []byte {
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': %s, 'shape': %s, }", descr, fortran, shape)
+	padding := 64 - (len(npyMagic)+4+len(header)+1)%64
+	header += strings.Repeat(" ", padding) + "\n"
+
+	var b bytes.Buffer
+	b.WriteString(npyMagic)
+	b.Write([]byte{1, 0})
+	binary.Write(&b, binary.LittleEndian, uint16(len(header)))
+	b.WriteString(header)
+	binary.Write(&b, order, values)
+	return b.Bytes()
+}
+
+// npz writes arrays to a .npz archive at path
+func npz(t *testing.T, path string, arrays map[string][]byte) {
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	for name, array := range arrays {
+		w, err := archive.Create(name + ".npy")
+		assert.NoError(t, err)
+		_, err = w.Write(array)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, archive.Close())
+}
+
+func TestReadNPY(t *testing.T) {
+	cases := []struct {
+		name     string
+		array    []byte
+		expected *mat.Dense
+		err      string
+	}{
+		{
+			"float64",
+			npy("<f8", "False", "(2, 3)", binary.LittleEndian, []float64{1, 2, 3, 4, 5, 6}),
+			mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+			"",
+		},
+		{
+			"fortran big endian int32",
+			npy(">i4", "True", "(2, 3)", binary.BigEndian, []int32{1, 4, 2, 5, 3, -6}),
+			mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, -6}),
+			"",
+		},
+		{
+			"1-d float32",
+			npy("<f4", "False", "(3,)", binary.LittleEndian, []float32{0.5, 1.5, -2}),
+			mat.NewDense(3, 1, []float64{0.5, 1.5, -2}),
+			"",
+		},
+		{
+			"0-d uint8",
+			npy("|u1", "False", "()", binary.LittleEndian, []uint8{7}),
+			mat.NewDense(1, 1, []float64{7}),
+			"",
+		},
+		{
+			"negative shape",
+			npy("<f8", "False", "(-1, 2)", binary.LittleEndian, []float64{1, 2}),
+			nil,
+			"npy shape \"-1, 2\" malformed",
+		},
+		{
+			"overflowing shape",
+			npy("<f8", "False", "(4611686018427387904, 4)", binary.LittleEndian, []float64{1}),
+			nil,
+			"npy shape [4611686018427387904 4] exceeds 268435456 elements",
+		},
+		{
+			"truncated shape",
+			npy("<f8", "False", "(100000, 100)", binary.LittleEndian, []float64{1, 2, 3}),
+			nil,
+			"npy array truncated after 0 of 10000000 values: unexpected EOF",
+		},
+	}
+
+	for _, c := range cases {
+		array, err := ReadNPY(bytes.NewReader(c.array))
+
+		if c.err != "" {
+			assert.EqualError(t, err, c.err, c.name)
+			continue
+		}
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, c.expected, &array, c.name)
+	}
+}
+
+func TestReadNPYUnsupported(t *testing.T) {
+	_, err := ReadNPY(bytes.NewReader(npy("<c16", "False", "(1,)", binary.LittleEndian, []float64{1, 0})))
+	assert.EqualError(t, err, "npy dtype \"<c16\" not supported")
+
+	_, err = ReadNPY(bytes.NewReader(npy("<f8", "False", "(1, 1, 1)", binary.LittleEndian, []float64{1})))
+	assert.EqualError(t, err, "npy array has 3 dimensions, at most 2 supported")
+
+	_, err = ReadNPY(strings.NewReader("not a numpy array"))
+	assert.EqualError(t, err, "npy missing magic string")
+}
+
+func TestStoreReadNPZ(t *testing.T) {
+	dir, err := os.MkdirTemp("", "npz")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	parametersPath, dataPath := filepath.Join(dir, "parameters.npz"), filepath.Join(dir, "data.npz")
+	npz(t, parametersPath, map[string][]byte{
+		"W1": npy("<f8", "False", "(3, 2)", binary.LittleEndian, []float64{1, 2, 3, 4, 5, 6}),
+		"b1": npy("<f8", "False", "(3, 1)", binary.LittleEndian, []float64{0.1, 0.2, 0.3}),
+		"W2": npy("<f8", "False", "(1, 3)", binary.LittleEndian, []float64{-1, 0, 1}),
+		"b2": npy("<f8", "False", "(1, 1)", binary.LittleEndian, []float64{0.5}),
+	})
+	npz(t, dataPath, map[string][]byte{
+		"X": npy("<f8", "False", "(2, 4)", binary.LittleEndian, []float64{1, 2, 3, 4, 5, 6, 7, 8}),
+		"Y": npy("<i8", "False", "(4,)", binary.LittleEndian, []int64{0, 1, 1, 0}),
+	})
+
+	s := NewStore(parametersPath)
+
+	parameters, err := s.ReadParameters()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 1}, parameters.Layers)
+	assert.Equal(t, mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}), &parameters.Weights[1])
+	assert.Equal(t, mat.NewDense(1, 1, []float64{0.5}), &parameters.Bias[2])
+
+	data, labels, err := s.ReadData(dataPath)
+	assert.NoError(t, err)
+	assert.Equal(t, mat.NewDense(2, 4, []float64{1, 2, 3, 4, 5, 6, 7, 8}), &data)
+	assert.Equal(t, mat.NewDense(1, 4, []float64{0, 1, 1, 0}), &labels)
+}
+
+func TestArraysParametersMissingBias(t *testing.T) {
+	_, err := ArraysParameters(map[string]mat.Dense{"W1": *mat.NewDense(1, 2, nil)})
+
+	assert.EqualError(t, err, "arrays missing b1")
+}
@@ -0,0 +1,200 @@
+package adapters
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+const (
+	// onnx versions written, IR version 7 is the first to support opset 13
+	onnxIRVersion = 7
+	onnxOpset     = 13
+
+	// onnx tensor element type of float32
+	onnxFloat = 1
+	// onnx attribute type of int
+	onnxAttributeInt = 2
+
+	// names of exported graph inputs and outputs
+	onnxInput  = "input"
+	onnxOutput = "output"
+)
+
+// onnx operator of activation functions
+var onnxActivations = map[string]string{
+	"relu":    "Relu",
+	"sigmoid": "Sigmoid",
+}
+
+// CreateONNX writes parameters as an ONNX model to path
+func (s *store) CreateONNX(path string, parameters lib.Parameters) error {
+	return writeAtomic(path, func(w io.Writer) error {
+		return WriteONNX(w, parameters)
+	})
+}
+
+// WriteONNX encodes parameters as an ONNX model of Gemm and activation nodes per layer
+//
+// The graph takes a float32 input of shape [N, features], a row per sample, and outputs [N, 1] scores. Each
+// layer is a Gemm of the input with transposed weights plus bias, followed by its activation. Batch
+// normalization is folded into weights and bias.
+func WriteONNX(w io.Writer, parameters lib.Parameters) error {
+	if err := parameters.Validate(0); err != nil {
+		return err
+	}
+	parameters = parameters.Fold()
+
+	var graph []byte
+	graph = protowire.AppendTag(graph, 2, protowire.BytesType)
+	graph = protowire.AppendString(graph, "binary_classify_nn")
+
+	layers := len(parameters.Layers)
+	previous := onnxInput
+
+	for layer := 1; layer < layers; layer++ {
+		suffix := strconv.Itoa(layer)
+		weightsName, biasName := weightsKey+suffix, biasKey+suffix
+
+		operator, ok := onnxActivations[parameters.Activation(layer)]
+		if !ok {
+			return fmt.Errorf("onnx export of %s activation not supported", parameters.Activation(layer))
+		}
+
+		output := "activation" + suffix
+		if layer == layers-1 {
+			output = onnxOutput
+		}
+
+		gemm := onnxNode("gemm"+suffix, "Gemm", []string{previous, weightsName, biasName}, "linear"+suffix)
+		gemm = appendMessage(gemm, 5, onnxIntAttribute("transB", 1))
+
+		graph = appendMessage(graph, 1, gemm)
+		graph = appendMessage(graph, 1, onnxNode(operator+suffix, operator, []string{"linear" + suffix}, output))
+
+		rows, cols := parameters.Weights[layer].Dims()
+		weights := make([]float64, 0, rows*cols)
+		for i := 0; i < rows; i++ {
+			weights = append(weights, parameters.Weights[layer].RawRowView(i)...)
+		}
+		bias := make([]float64, rows)
+		for i := range bias {
+			bias[i] = parameters.Bias[layer].At(i, 0)
+		}
+
+		graph = appendMessage(graph, 5, onnxTensor(weightsName, []int64{int64(rows), int64(cols)}, weights))
+		graph = appendMessage(graph, 5, onnxTensor(biasName, []int64{int64(rows)}, bias))
+
+		previous = output
+	}
+
+	graph = appendMessage(graph, 11, onnxValueInfo(onnxInput, int64(parameters.Layers[0])))
+	graph = appendMessage(graph, 12, onnxValueInfo(onnxOutput, int64(parameters.Layers[layers-1])))
+
+	var opset []byte
+	opset = protowire.AppendTag(opset, 2, protowire.VarintType)
+	opset = protowire.AppendVarint(opset, onnxOpset)
+
+	var model []byte
+	model = protowire.AppendTag(model, 1, protowire.VarintType)
+	model = protowire.AppendVarint(model, onnxIRVersion)
+	model = protowire.AppendTag(model, 2, protowire.BytesType)
+	model = protowire.AppendString(model, "go-binary-classify-nn")
+	model = appendMessage(model, 7, graph)
+	model = appendMessage(model, 8, opset)
+
+	_, err := w.Write(model)
+	return err
+}
+
+// onnxNode encodes a NodeProto
+func onnxNode(name, operator string, inputs []string, output string) []byte {
+	var node []byte
+	for _, input := range inputs {
+		node = protowire.AppendTag(node, 1, protowire.BytesType)
+		node = protowire.AppendString(node, input)
+	}
+	node = protowire.AppendTag(node, 2, protowire.BytesType)
+	node = protowire.AppendString(node, output)
+	node = protowire.AppendTag(node, 3, protowire.BytesType)
+	node = protowire.AppendString(node, name)
+	node = protowire.AppendTag(node, 4, protowire.BytesType)
+	node = protowire.AppendString(node, operator)
+	return node
+}
+
+// onnxIntAttribute encodes an AttributeProto of an int
+func onnxIntAttribute(name string, value int64) []byte {
+	var attribute []byte
+	attribute = protowire.AppendTag(attribute, 1, protowire.BytesType)
+	attribute = protowire.AppendString(attribute, name)
+	attribute = protowire.AppendTag(attribute, 3, protowire.VarintType)
+	attribute = protowire.AppendVarint(attribute, uint64(value))
+	attribute = protowire.AppendTag(attribute, 20, protowire.VarintType)
+	attribute = protowire.AppendVarint(attribute, onnxAttributeInt)
+	return attribute
+}
+
+// onnxTensor encodes a float32 TensorProto of values in row-major order
+func onnxTensor(name string, dims []int64, values []float64) []byte {
+	var tensor []byte
+	for _, dim := range dims {
+		tensor = protowire.AppendTag(tensor, 1, protowire.VarintType)
+		tensor = protowire.AppendVarint(tensor, uint64(dim))
+	}
+	tensor = protowire.AppendTag(tensor, 2, protowire.VarintType)
+	tensor = protowire.AppendVarint(tensor, onnxFloat)
+	tensor = protowire.AppendTag(tensor, 8, protowire.BytesType)
+	tensor = protowire.AppendString(tensor, name)
+
+	raw := make([]byte, 4*len(values))
+	for i, value := range values {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(float32(value)))
+	}
+	tensor = protowire.AppendTag(tensor, 9, protowire.BytesType)
+	tensor = protowire.AppendBytes(tensor, raw)
+
+	return tensor
+}
+
+// onnxValueInfo encodes a ValueInfoProto of a float32 tensor of shape [N, width]
+func onnxValueInfo(name string, width int64) []byte {
+	var batch []byte
+	batch = protowire.AppendTag(batch, 2, protowire.BytesType)
+	batch = protowire.AppendString(batch, "N")
+
+	var features []byte
+	features = protowire.AppendTag(features, 1, protowire.VarintType)
+	features = protowire.AppendVarint(features, uint64(width))
+
+	var shape []byte
+	shape = appendMessage(shape, 1, batch)
+	shape = appendMessage(shape, 1, features)
+
+	var tensor []byte
+	tensor = protowire.AppendTag(tensor, 1, protowire.VarintType)
+	tensor = protowire.AppendVarint(tensor, onnxFloat)
+	tensor = appendMessage(tensor, 2, shape)
+
+	var valueType []byte
+	valueType = appendMessage(valueType, 1, tensor)
+
+	var info []byte
+	info = protowire.AppendTag(info, 1, protowire.BytesType)
+	info = protowire.AppendString(info, name)
+	info = appendMessage(info, 2, valueType)
+
+	return info
+}
+
+// appendMessage appends an embedded message field
+func appendMessage(b []byte, number protowire.Number, message []byte) []byte {
+	b = protowire.AppendTag(b, number, protowire.BytesType)
+	return protowire.AppendBytes(b, message)
+}
@@ -0,0 +1,95 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// protoFields decodes varint and length delimited fields of a protobuf message by field number
+func protoFields(t *testing.T, b []byte) map[protowire.Number][]interface{} {
+	fields := map[protowire.Number][]interface{}{}
+	for len(b) > 0 {
+		number, kind, n := protowire.ConsumeTag(b)
+		assert.True(t, n > 0)
+		b = b[n:]
+
+		switch kind {
+		case protowire.VarintType:
+			value, n := protowire.ConsumeVarint(b)
+			assert.True(t, n > 0)
+			fields[number] = append(fields[number], value)
+			b = b[n:]
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(b)
+			assert.True(t, n > 0)
+			fields[number] = append(fields[number], value)
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %d", kind)
+		}
+	}
+	return fields
+}
+
+func TestWriteONNX(t *testing.T) {
+	parameters := lib.NewParameters([]int{3, 2, 1})
+	parameters.Bias[1] = *mat.NewDense(2, 1, []float64{0.25, -0.5})
+	parameters.Bias[2] = *mat.NewDense(1, 1, []float64{0.125})
+
+	var b bytes.Buffer
+	assert.NoError(t, WriteONNX(&b, parameters))
+
+	model := protoFields(t, b.Bytes())
+	assert.Equal(t, []interface{}{uint64(onnxIRVersion)}, model[1])
+
+	opset := protoFields(t, model[8][0].([]byte))
+	assert.Equal(t, []interface{}{uint64(onnxOpset)}, opset[2])
+
+	graph := protoFields(t, model[7][0].([]byte))
+
+	var operators, outputs []string
+	for _, node := range graph[1] {
+		fields := protoFields(t, node.([]byte))
+		operators = append(operators, string(fields[4][0].([]byte)))
+		outputs = append(outputs, string(fields[2][0].([]byte)))
+	}
+	assert.Equal(t, []string{"Gemm", "Relu", "Gemm", "Sigmoid"}, operators)
+	assert.Equal(t, onnxOutput, outputs[len(outputs)-1])
+
+	initializers := map[string]*mat.Dense{}
+	for _, tensor := range graph[5] {
+		fields := protoFields(t, tensor.([]byte))
+		assert.Equal(t, []interface{}{uint64(onnxFloat)}, fields[2])
+
+		raw := fields[9][0].([]byte)
+		values := make([]float64, len(raw)/4)
+		for i := range values {
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:])))
+		}
+
+		rows, cols := int(fields[1][0].(uint64)), 1
+		if len(fields[1]) == 2 {
+			cols = int(fields[1][1].(uint64))
+		}
+		initializers[string(fields[8][0].([]byte))] = mat.NewDense(rows, cols, values)
+	}
+
+	assert.Len(t, initializers, 4)
+	for layer := 1; layer < len(parameters.Layers); layer++ {
+		name := strconv.Itoa(layer)
+		assert.True(t, mat.EqualApprox(&parameters.Weights[layer], initializers["W"+name], 1e-7), "W"+name)
+		assert.True(t, mat.EqualApprox(&parameters.Bias[layer], initializers["b"+name], 1e-7), "b"+name)
+	}
+
+	assert.Len(t, graph[11], 1)
+	assert.Len(t, graph[12], 1)
+}
@@ -0,0 +1,172 @@
+package adapters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+const (
+	// record keys used in quantized parameters CSV in addition to layers and bias
+	formatKey          = "format"
+	quantizedKey       = "Q"
+	weightScalesKey    = "ws"
+	activationScaleKey = "as"
+
+	// format of quantized parameters
+	int8Format = "int8"
+)
+
+// CreateQuantized writes int8 quantized parameters in CSV format
+//
+// Records are keyed like parameters with a leading "format,int8" record: "Q<layer>" for a row of int8
+// weights, "ws<layer>" for weight scales per unit, "as<layer>" for the input activation scale and "b<layer>"
+// for the bias vector.
+func (s *store) CreateQuantized(path string, quantized lib.QuantizedParameters) error {
+	return writeAtomic(path, func(w io.Writer) error {
+		return writeQuantized(w, quantized)
+	})
+}
+
+// ReadQuantized reads int8 quantized parameters from CSV
+func (s *store) ReadQuantized(path string) (lib.QuantizedParameters, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return lib.QuantizedParameters{}, err
+	}
+	defer file.Close()
+
+	return readQuantized(file)
+}
+
+// CreateComparison writes comparison of quantized and float predictions as JSON
+func (s *store) CreateComparison(path string, comparison lib.QuantizationComparison) error {
+	return writeJSON(path, comparison)
+}
+
+func writeQuantized(w io.Writer, quantized lib.QuantizedParameters) error {
+	writer := csv.NewWriter(w)
+
+	writer.Write([]string{formatKey, int8Format})
+
+	layers := make([]string, len(quantized.Layers))
+	for i, nodes := range quantized.Layers {
+		layers[i] = strconv.Itoa(nodes)
+	}
+	writer.Write(append([]string{layersKey}, layers...))
+
+	for layer := 1; layer < len(quantized.Layers); layer++ {
+		suffix := strconv.Itoa(layer)
+		inputs := quantized.Layers[layer-1]
+
+		for i := 0; i < quantized.Layers[layer]; i++ {
+			row := quantized.Weights[layer][i*inputs : (i+1)*inputs]
+			fields := make([]string, len(row))
+			for j, w := range row {
+				fields[j] = strconv.Itoa(int(w))
+			}
+			writer.Write(append([]string{quantizedKey + suffix}, fields...))
+		}
+
+		writer.Write(append([]string{weightScalesKey + suffix}, formatFloats(quantized.WeightScales[layer])...))
+		writer.Write(append([]string{activationScaleKey + suffix}, formatFloats([]float64{quantized.ActivationScales[layer]})...))
+		writer.Write(append([]string{biasKey + suffix}, formatFloats(quantized.Bias[layer])...))
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func readQuantized(r io.Reader) (lib.QuantizedParameters, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return lib.QuantizedParameters{}, err
+	}
+
+	if len(records) < 2 || records[0][0] != formatKey || len(records[0]) != 2 || records[0][1] != int8Format {
+		return lib.QuantizedParameters{}, fmt.Errorf("quantized parameters missing %s,%s record", formatKey, int8Format)
+	}
+
+	if records[1][0] != layersKey {
+		return lib.QuantizedParameters{}, fmt.Errorf("quantized parameters missing %s record", layersKey)
+	}
+
+	layers := make([]int, len(records[1])-1)
+	for i, field := range records[1][1:] {
+		if layers[i], err = strconv.Atoi(field); err != nil {
+			return lib.QuantizedParameters{}, err
+		}
+	}
+
+	quantized := lib.QuantizedParameters{
+		Layers:           layers,
+		Weights:          make([][]int8, len(layers)),
+		WeightScales:     make([][]float64, len(layers)),
+		ActivationScales: make([]float64, len(layers)),
+		Bias:             make([][]float64, len(layers)),
+	}
+
+	for _, record := range records[2:] {
+		key := record[0]
+
+		for _, prefix := range []string{weightScalesKey, activationScaleKey, quantizedKey, biasKey} {
+			if len(key) <= len(prefix) || key[:len(prefix)] != prefix {
+				continue
+			}
+
+			layer, err := parseLayer(key[len(prefix):], layers)
+			if err != nil {
+				return lib.QuantizedParameters{}, err
+			}
+
+			if prefix == quantizedKey {
+				for _, field := range record[1:] {
+					w, err := strconv.ParseInt(field, 10, 8)
+					if err != nil {
+						return lib.QuantizedParameters{}, err
+					}
+					quantized.Weights[layer] = append(quantized.Weights[layer], int8(w))
+				}
+				break
+			}
+
+			values, err := parseFloats(record[1:])
+			if err != nil {
+				return lib.QuantizedParameters{}, err
+			}
+
+			switch prefix {
+			case weightScalesKey:
+				quantized.WeightScales[layer] = values
+			case activationScaleKey:
+				if len(values) != 1 {
+					return lib.QuantizedParameters{}, fmt.Errorf("quantized parameters layer %d has %d activation scales", layer, len(values))
+				}
+				quantized.ActivationScales[layer] = values[0]
+			case biasKey:
+				quantized.Bias[layer] = values
+			}
+			break
+		}
+	}
+
+	if err := quantized.Validate(0); err != nil {
+		return lib.QuantizedParameters{}, err
+	}
+
+	for layer := 1; layer < len(layers); layer++ {
+		if quantized.ActivationScales[layer] <= 0 {
+			return lib.QuantizedParameters{}, fmt.Errorf("quantized parameters layer %d activation scale %g is not positive", layer, quantized.ActivationScales[layer])
+		}
+	}
+
+	return quantized, nil
+}
@@ -0,0 +1,51 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+func TestStoreQuantized(t *testing.T) {
+	dir, err := os.MkdirTemp("", "quantized")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "parameters.int8.csv")
+	quantized := lib.QuantizedParameters{
+		Layers:           []int{2, 2, 1},
+		Weights:          [][]int8{nil, {127, -64, -128, 3}, {5, 127}},
+		WeightScales:     [][]float64{nil, {0.01, 0.002}, {0.02}},
+		ActivationScales: []float64{0, 0.015748031496062992, 0.5},
+		Bias:             [][]float64{nil, {0.5, -0.5}, {0.25}},
+	}
+
+	s := NewStore(filepath.Join(dir, "parameters.csv"))
+	assert.NoError(t, s.CreateQuantized(path, quantized))
+
+	read, err := s.ReadQuantized(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, quantized, read)
+}
+
+func TestReadQuantizedInvalid(t *testing.T) {
+	_, err := readQuantized(strings.NewReader("layers,2,1\nW1,1,2\nb1,0\n"))
+	assert.EqualError(t, err, "quantized parameters missing format,int8 record")
+
+	_, err = readQuantized(strings.NewReader("format,int8\nlayers,2,1\nQ1,1\nws1,1\nas1,1\nb1,0\n"))
+	assert.EqualError(t, err, "quantized parameters layer 1 does not match 1 x 2")
+}
+
+func TestReadQuantizedActivationScale(t *testing.T) {
+	_, err := readQuantized(strings.NewReader("format,int8\nlayers,2,1\nQ1,1,2\nws1,1\nb1,0\n"))
+	assert.EqualError(t, err, "quantized parameters layer 1 activation scale 0 is not positive")
+
+	_, err = readQuantized(strings.NewReader("format,int8\nlayers,2,1\nQ1,1,2\nws1,1\nas1,-0.5\nb1,0\n"))
+	assert.EqualError(t, err, "quantized parameters layer 1 activation scale -0.5 is not positive")
+}
@@ -0,0 +1,285 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Staging stage of models under evaluation
+	Staging = "staging"
+	// Production stage of models serving traffic
+	Production = "production"
+
+	// registry file names
+	indexFile      = "registry.json"
+	parametersFile = "parameters.csv"
+)
+
+// ModelVersion registered with a registry
+type ModelVersion struct {
+	Name         string    `json:"name"`
+	Version      int       `json:"version"`
+	Path         string    `json:"path"`
+	Source       string    `json:"source"`
+	RegisteredAt time.Time `json:"registered_at"`
+	// Stages the version is currently promoted to
+	Stages []string `json:"stages,omitempty"`
+}
+
+// registryIndex of a model's versions and stage promotions
+type registryIndex struct {
+	Versions []ModelVersion `json:"versions"`
+	// Stages with promoted versions in order, the last being current
+	Stages map[string][]int `json:"stages"`
+}
+
+// current version of stage, 0 when none is promoted
+func (i *registryIndex) current(stage string) int {
+	if promoted := i.Stages[stage]; len(promoted) > 0 {
+		return promoted[len(promoted)-1]
+	}
+	return 0
+}
+
+// registry adapter storing versioned parameters and their metadata under a root directory
+//
+// Each model has a directory holding a registry.json index and a directory per version, v1, v2 and so
+// on, containing parameters.csv and its metadata. The registry is meant for a single writer at a time.
+type registry struct {
+	// root directory of models
+	root string
+}
+
+// Register copies parameters at path, along with their metadata when present, as the next version of name
+func (r *registry) Register(name, path string) (ModelVersion, error) {
+	source := NewStore(path)
+	parameters, err := source.ReadParameters()
+	if err != nil {
+		return ModelVersion{}, err
+	}
+	if err := parameters.Validate(0); err != nil {
+		return ModelVersion{}, err
+	}
+
+	index, err := r.index(name)
+	if err != nil {
+		return ModelVersion{}, err
+	}
+
+	version := ModelVersion{
+		Name:         name,
+		Version:      len(index.Versions) + 1,
+		Source:       path,
+		RegisteredAt: time.Now().UTC(),
+	}
+	version.Path = r.parametersPath(name, version.Version)
+
+	if err := os.MkdirAll(filepath.Dir(version.Path), 0755); err != nil {
+		return ModelVersion{}, err
+	}
+
+	destination := NewStore(version.Path)
+	if err := destination.CreateParameters(parameters); err != nil {
+		return ModelVersion{}, err
+	}
+
+	if err := copyFile(source.MetadataPath(), destination.MetadataPath()); err != nil && !os.IsNotExist(err) {
+		return ModelVersion{}, err
+	}
+
+	index.Versions = append(index.Versions, version)
+
+	return version, r.writeIndex(name, index)
+}
+
+// List registered versions of models ordered by name and version
+func (r *registry) List() ([]ModelVersion, error) {
+	entries, err := os.ReadDir(r.root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []ModelVersion
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		index, err := r.index(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, version := range index.Versions {
+			for stage := range index.Stages {
+				if index.current(stage) == version.Version {
+					version.Stages = append(version.Stages, stage)
+				}
+			}
+			sort.Strings(version.Stages)
+			versions = append(versions, version)
+		}
+	}
+
+	return versions, nil
+}
+
+// Promote version of name to stage
+func (r *registry) Promote(name string, version int, stage string) error {
+	if err := validateStage(stage); err != nil {
+		return err
+	}
+
+	index, err := r.index(name)
+	if err != nil {
+		return err
+	}
+
+	if version < 1 || version > len(index.Versions) {
+		return fmt.Errorf("model %s has no version %d", name, version)
+	}
+
+	if index.current(stage) == version {
+		return nil
+	}
+
+	index.Stages[stage] = append(index.Stages[stage], version)
+
+	return r.writeIndex(name, index)
+}
+
+// Rollback stage of name to the previously promoted version returning it
+func (r *registry) Rollback(name, stage string) (int, error) {
+	if err := validateStage(stage); err != nil {
+		return 0, err
+	}
+
+	index, err := r.index(name)
+	if err != nil {
+		return 0, err
+	}
+
+	promoted := index.Stages[stage]
+	if len(promoted) < 2 {
+		return 0, fmt.Errorf("model %s has no previous %s version", name, stage)
+	}
+
+	index.Stages[stage] = promoted[:len(promoted)-1]
+
+	return index.current(stage), r.writeIndex(name, index)
+}
+
+// Resolve a model reference to its parameters path
+//
+// References are name@stage, name@version (e.g. name@3) or name@latest.
+func (r *registry) Resolve(ref string) (string, error) {
+	fields := strings.SplitN(ref, "@", 2)
+	if len(fields) != 2 {
+		return "", fmt.Errorf("model reference %q is not name@stage or name@version", ref)
+	}
+	name, selector := fields[0], fields[1]
+
+	index, err := r.index(name)
+	if err != nil {
+		return "", err
+	}
+
+	if len(index.Versions) == 0 {
+		return "", fmt.Errorf("model %s is not registered", name)
+	}
+
+	version := 0
+	switch selector {
+	case "latest":
+		version = len(index.Versions)
+	case Staging, Production:
+		if version = index.current(selector); version == 0 {
+			return "", fmt.Errorf("model %s has no %s version", name, selector)
+		}
+	default:
+		if version, err = strconv.Atoi(strings.TrimPrefix(selector, "v")); err != nil || version < 1 || version > len(index.Versions) {
+			return "", fmt.Errorf("model %s has no version %s", name, selector)
+		}
+	}
+
+	return r.parametersPath(name, version), nil
+}
+
+// IndexPath of model name, rewritten on every registration, promotion and rollback
+func (r *registry) IndexPath(name string) string {
+	return filepath.Join(r.root, name, indexFile)
+}
+
+func (r *registry) parametersPath(name string, version int) string {
+	return filepath.Join(r.root, name, "v"+strconv.Itoa(version), parametersFile)
+}
+
+func (r *registry) index(name string) (registryIndex, error) {
+	index := registryIndex{Stages: map[string][]int{}}
+
+	if err := validateName(name); err != nil {
+		return index, err
+	}
+
+	err := readJSON(r.IndexPath(name), &index)
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+
+	if index.Stages == nil {
+		index.Stages = map[string][]int{}
+	}
+
+	return index, err
+}
+
+func (r *registry) writeIndex(name string, index registryIndex) error {
+	return writeAtomic(r.IndexPath(name), func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(index)
+	})
+}
+
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `@/\`) {
+		return fmt.Errorf("invalid model name %q", name)
+	}
+	return nil
+}
+
+func validateStage(stage string) error {
+	if stage != Staging && stage != Production {
+		return fmt.Errorf("invalid stage %q, expected %s or %s", stage, Staging, Production)
+	}
+	return nil
+}
+
+func copyFile(source, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return writeAtomic(destination, func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	})
+}
+
+// NewRegistry adapter with models stored under root
+func NewRegistry(root string) *registry {
+	return &registry{root: root}
+}
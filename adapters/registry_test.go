@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRegistry(t *testing.T) (*registry, string, func()) {
+	dir, err := os.MkdirTemp("", "registry")
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, "parameters.csv")
+	s := NewStore(path)
+	assert.NoError(t, s.CreateParameters(testParameters()))
+	assert.NoError(t, s.CreateMetadata(Metadata{Notes: "baseline"}))
+
+	return NewRegistry(filepath.Join(dir, "models")), path, func() { os.RemoveAll(dir) }
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r, path, cleanup := newTestRegistry(t)
+	defer cleanup()
+
+	first, err := r.Register("churn", path)
+	assert.NoError(t, err)
+	second, err := r.Register("churn", path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, first.Version)
+	assert.Equal(t, 2, second.Version)
+	assert.Equal(t, filepath.Join(r.root, "churn", "v2", "parameters.csv"), second.Path)
+
+	parameters, err := NewStore(second.Path).ReadParameters()
+	assert.NoError(t, err)
+	assert.Equal(t, testParameters().Layers, parameters.Layers)
+
+	metadata, err := NewStore(second.Path).ReadMetadata()
+	assert.NoError(t, err)
+	assert.Equal(t, "baseline", metadata.Notes)
+
+	_, err = r.Register("../churn", path)
+	assert.EqualError(t, err, "invalid model name \"../churn\"")
+}
+
+func TestRegistryPromoteRollback(t *testing.T) {
+	r, path, cleanup := newTestRegistry(t)
+	defer cleanup()
+
+	for i := 0; i < 3; i++ {
+		_, err := r.Register("churn", path)
+		assert.NoError(t, err)
+	}
+
+	_, err := r.Resolve("churn@production")
+	assert.EqualError(t, err, "model churn has no production version")
+
+	assert.NoError(t, r.Promote("churn", 1, Production))
+	assert.NoError(t, r.Promote("churn", 3, Production))
+	assert.NoError(t, r.Promote("churn", 2, Staging))
+	assert.EqualError(t, r.Promote("churn", 4, Production), "model churn has no version 4")
+	assert.EqualError(t, r.Promote("churn", 1, "qa"), "invalid stage \"qa\", expected staging or production")
+
+	resolved, err := r.Resolve("churn@production")
+	assert.NoError(t, err)
+	assert.Equal(t, r.parametersPath("churn", 3), resolved)
+
+	versions, err := r.List()
+	assert.NoError(t, err)
+	assert.Len(t, versions, 3)
+	assert.Nil(t, versions[0].Stages)
+	assert.Equal(t, []string{Staging}, versions[1].Stages)
+	assert.Equal(t, []string{Production}, versions[2].Stages)
+
+	version, err := r.Rollback("churn", Production)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	resolved, err = r.Resolve("churn@production")
+	assert.NoError(t, err)
+	assert.Equal(t, r.parametersPath("churn", 1), resolved)
+
+	_, err = r.Rollback("churn", Production)
+	assert.EqualError(t, err, "model churn has no previous production version")
+}
+
+func TestRegistryResolve(t *testing.T) {
+	r, path, cleanup := newTestRegistry(t)
+	defer cleanup()
+
+	for i := 0; i < 2; i++ {
+		_, err := r.Register("churn", path)
+		assert.NoError(t, err)
+	}
+
+	cases := map[string]string{
+		"churn@latest": r.parametersPath("churn", 2),
+		"churn@1":      r.parametersPath("churn", 1),
+		"churn@v2":     r.parametersPath("churn", 2),
+	}
+	for ref, expected := range cases {
+		resolved, err := r.Resolve(ref)
+		assert.NoError(t, err, ref)
+		assert.Equal(t, expected, resolved, ref)
+	}
+
+	_, err := r.Resolve("churn@3")
+	assert.EqualError(t, err, "model churn has no version 3")
+
+	_, err = r.Resolve("fraud@latest")
+	assert.EqualError(t, err, "model fraud is not registered")
+
+	_, err = r.Resolve("churn")
+	assert.EqualError(t, err, "model reference \"churn\" is not name@stage or name@version")
+}
@@ -0,0 +1,375 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// defaultMaxBodyBytes of a request body when ServerOptions leave MaxBodyBytes zero
+const defaultMaxBodyBytes = 1 << 20
+
+// statusClientClosedRequest (non-standard) of requests whose client went away before a response
+const statusClientClosedRequest = 499
+
+// predictRequest of a single sample
+type predictRequest struct {
+	Features []float64 `json:"features"`
+}
+
+// batchRequest of many samples
+type batchRequest struct {
+	Samples [][]float64 `json:"samples"`
+}
+
+// batchResponse of predictions in request order
+type batchResponse struct {
+	Predictions []lib.Prediction `json:"predictions"`
+}
+
+// modelResponse describing served model
+type modelResponse struct {
+	Layers      []int     `json:"layers"`
+	Activations []string  `json:"activations"`
+	Parameters  int       `json:"parameters"`
+	Threshold   float64   `json:"threshold"`
+	Version     int       `json:"version"`
+	Loaded      time.Time `json:"loaded"`
+}
+
+// errorResponse of failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Loader of parameters served
+type Loader func() (lib.Parameters, error)
+
+// QuantizedLoader of int8 quantized parameters served
+type QuantizedLoader func() (lib.QuantizedParameters, error)
+
+// ServerOptions configuring Server
+type ServerOptions struct {
+	// InputWidth of features parameters must accept, any width when zero
+	InputWidth int
+	// MaxBatch of single sample predictions coalesced into a forward pass, batching is disabled below 2
+	MaxBatch int
+	// MaxWait for a batch to fill before it is classified
+	MaxWait time.Duration
+	// MaxBodyBytes of a request body, defaultMaxBodyBytes when zero
+	MaxBodyBytes int64
+}
+
+// model snapshot served, never modified once stored
+type model struct {
+	parameters lib.Parameters
+	// quantized parameters served instead of parameters when loaded by a QuantizedLoader
+	quantized *lib.QuantizedParameters
+	version   int
+	loaded    time.Time
+}
+
+// layers of network served
+func (m *model) layers() []int {
+	if m.quantized != nil {
+		return m.quantized.Layers
+	}
+	return m.parameters.Layers
+}
+
+// activation function name of layer
+func (m *model) activation(layer int) string {
+	if m.quantized != nil {
+		return m.quantized.Activation(layer)
+	}
+	return m.parameters.Activation(layer)
+}
+
+// classify samples with parameters served
+func (m *model) classify(samples [][]float64) ([]lib.Prediction, error) {
+	if m.quantized != nil {
+		return m.quantized.Classify(samples)
+	}
+	return lib.Classify(m.parameters, samples)
+}
+
+// Server adapter exposing classification of samples over HTTP
+//
+// Each request classifies with the model snapshot current when it started. Reloading validates new
+// parameters before swapping the snapshot atomically, so in-flight requests finish on the old model and
+// no request observes a partially loaded one.
+//
+// Single sample predictions are coalesced into batched forward passes when batching is enabled. Reloading
+// over HTTP is served by Admin rather than the server itself so it can be kept off public listeners.
+type Server struct {
+	load          Loader
+	loadQuantized QuantizedLoader
+	options       ServerOptions
+	model         atomic.Value
+	reloading     sync.Mutex
+	batcher       *Batcher
+	metrics       *serverMetrics
+	handler       http.Handler
+	admin         http.Handler
+}
+
+// ServeHTTP routes requests to endpoint handlers
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// Admin handler of unauthenticated administration endpoints, /admin/reload, to serve on a private listener
+func (s *Server) Admin() http.Handler {
+	return s.admin
+}
+
+// Reload parameters using loader, keeping the current model when they fail validation
+func (s *Server) Reload() error {
+	s.reloading.Lock()
+	defer s.reloading.Unlock()
+
+	next, err := s.loadModel()
+	if err != nil {
+		return err
+	}
+	if current := s.current(); current != nil {
+		next.version = current.version + 1
+	}
+
+	s.model.Store(next)
+	s.metrics.version.Set(float64(next.version))
+
+	return nil
+}
+
+// loadModel using loader validated against input width
+func (s *Server) loadModel() (*model, error) {
+	if s.loadQuantized != nil {
+		quantized, err := s.loadQuantized()
+		if err != nil {
+			return nil, err
+		}
+		if err := quantized.Validate(s.options.InputWidth); err != nil {
+			return nil, err
+		}
+		return &model{quantized: &quantized, loaded: time.Now()}, nil
+	}
+
+	parameters, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := parameters.Validate(s.options.InputWidth); err != nil {
+		return nil, err
+	}
+	return &model{parameters: parameters, loaded: time.Now()}, nil
+}
+
+// Close server releasing batcher
+func (s *Server) Close() {
+	if s.batcher != nil {
+		s.batcher.Close()
+	}
+}
+
+// Parameters currently served, zero when serving quantized parameters
+func (s *Server) Parameters() lib.Parameters {
+	return s.current().parameters
+}
+
+// Layers of network currently served
+func (s *Server) Layers() []int {
+	return s.current().layers()
+}
+
+func (s *Server) current() *model {
+	m, _ := s.model.Load().(*model)
+	return m
+}
+
+func (s *Server) predict(w http.ResponseWriter, r *http.Request) {
+	var request predictRequest
+	if !decode(w, r, s.options.MaxBodyBytes, &request) {
+		return
+	}
+
+	if s.batcher != nil {
+		prediction, err := s.batcher.Classify(r.Context(), request.Features)
+		if err != nil {
+			respond(w, batcherStatus(err), errorResponse{Error: err.Error()})
+			return
+		}
+
+		s.metrics.observe(prediction)
+		respond(w, http.StatusOK, prediction)
+		return
+	}
+
+	predictions, err := s.classify([][]float64{request.Features})
+	if err != nil {
+		respond(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	s.metrics.observe(predictions...)
+	respond(w, http.StatusOK, predictions[0])
+}
+
+func (s *Server) predictBatch(w http.ResponseWriter, r *http.Request) {
+	var request batchRequest
+	if !decode(w, r, s.options.MaxBodyBytes, &request) {
+		return
+	}
+
+	predictions, err := s.classify(request.Samples)
+	if err != nil {
+		respond(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	s.metrics.observe(predictions...)
+	respond(w, http.StatusOK, batchResponse{Predictions: predictions})
+}
+
+// batcherStatus of a batcher classification error, errors of the sample itself are bad requests
+func batcherStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrBatcherClosed), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// classify samples with the current model snapshot
+func (s *Server) classify(samples [][]float64) ([]lib.Prediction, error) {
+	return s.current().classify(samples)
+}
+
+func (s *Server) describe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respond(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+		return
+	}
+
+	m := s.current()
+	layers := m.layers()
+	response := modelResponse{
+		Layers:      layers,
+		Activations: make([]string, len(layers)),
+		Threshold:   lib.Threshold,
+		Version:     m.version,
+		Loaded:      m.loaded,
+	}
+	for layer, neurons := range layers {
+		response.Activations[layer] = m.activation(layer)
+		if layer > 0 {
+			response.Parameters += neurons*layers[layer-1] + neurons
+		}
+	}
+
+	respond(w, http.StatusOK, response)
+}
+
+func (s *Server) reload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respond(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+		return
+	}
+
+	if err := s.Reload(); err != nil {
+		respond(w, http.StatusUnprocessableEntity, errorResponse{Error: err.Error()})
+		return
+	}
+
+	respond(w, http.StatusOK, map[string]int{"version": s.current().version})
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) ready(w http.ResponseWriter, r *http.Request) {
+	if s.current() == nil {
+		respond(w, http.StatusServiceUnavailable, map[string]string{"status": "no model"})
+		return
+	}
+
+	respond(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// decode JSON body of POST request of at most maxBytes into v responding with an error when it fails
+func decode(w http.ResponseWriter, r *http.Request, maxBytes int64, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		respond(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+		return false
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBytes)).Decode(v); err != nil {
+		respond(w, http.StatusBadRequest, errorResponse{Error: "invalid JSON: " + err.Error()})
+		return false
+	}
+
+	return true
+}
+
+func respond(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// NewServer adapter serving parameters from loader
+//
+// When options leave InputWidth zero it is set to the width of the initially loaded parameters.
+func NewServer(load Loader, options ServerOptions) (*Server, error) {
+	return newServer(&Server{load: load, options: options, metrics: newServerMetrics()})
+}
+
+// NewQuantizedServer adapter serving int8 quantized parameters from loader like NewServer
+func NewQuantizedServer(load QuantizedLoader, options ServerOptions) (*Server, error) {
+	return newServer(&Server{loadQuantized: load, options: options, metrics: newServerMetrics()})
+}
+
+// newServer loading its initial model and routing its endpoints
+func newServer(s *Server) (*Server, error) {
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	if s.options.InputWidth == 0 {
+		s.options.InputWidth = s.current().layers()[0]
+	}
+
+	if s.options.MaxBodyBytes == 0 {
+		s.options.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
+	if s.options.MaxBatch > 1 {
+		s.batcher = NewBatcher(s.classify, s.options.MaxBatch, s.options.MaxWait)
+	}
+
+	mux := http.NewServeMux()
+	s.metrics.handle(mux, "/v1/predict", s.predict)
+	s.metrics.handle(mux, "/v1/predict/batch", s.predictBatch)
+	s.metrics.handle(mux, "/v1/model", s.describe)
+	s.metrics.handle(mux, "/healthz", s.health)
+	s.metrics.handle(mux, "/readyz", s.ready)
+	s.metrics.handle(mux, "/metrics", s.metrics.registry.ServeHTTP)
+
+	s.handler = s.metrics.instrument(mux)
+
+	admin := http.NewServeMux()
+	s.metrics.handle(admin, "/admin/reload", s.reload)
+	s.admin = s.metrics.instrument(admin)
+
+	return s, nil
+}
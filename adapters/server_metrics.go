@@ -0,0 +1,115 @@
+package adapters
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/metrics"
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// serverMetrics of requests and predictions served
+type serverMetrics struct {
+	registry     *metrics.Registry
+	requests     *metrics.Counter
+	latency      *metrics.Histogram
+	scores       *metrics.Histogram
+	predictions  *metrics.Counter
+	positiveRate *metrics.Gauge
+	version      *metrics.Gauge
+
+	positives uint64
+	total     uint64
+
+	// paths labelled, others are labelled unmatched to bound cardinality
+	paths map[string]bool
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// handle path on mux registering it for labelling
+func (m *serverMetrics) handle(mux *http.ServeMux, path string, handler http.HandlerFunc) {
+	m.paths[path] = true
+	mux.HandleFunc(path, handler)
+}
+
+// instrument handler counting requests and observing latency by path and status code
+func (m *serverMetrics) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		path := r.URL.Path
+		if !m.paths[path] {
+			path = "unmatched"
+		}
+
+		m.requests.Inc(path, strconv.Itoa(recorder.status))
+		m.latency.Observe(time.Since(start).Seconds(), path)
+	})
+}
+
+// observe predictions served
+func (m *serverMetrics) observe(predictions ...lib.Prediction) {
+	for _, prediction := range predictions {
+		m.scores.Observe(prediction.Score)
+		m.predictions.Inc(strconv.Itoa(prediction.Label))
+		if prediction.Label == 1 {
+			atomic.AddUint64(&m.positives, 1)
+		}
+	}
+
+	total := atomic.AddUint64(&m.total, uint64(len(predictions)))
+	m.positiveRate.Set(float64(atomic.LoadUint64(&m.positives)) / float64(total))
+}
+
+func newServerMetrics() *serverMetrics {
+	registry := metrics.NewRegistry()
+
+	return &serverMetrics{
+		paths:    map[string]bool{},
+		registry: registry,
+		requests: registry.Counter(
+			"classifier_http_requests_total",
+			"HTTP requests served by path and status code.",
+			"path", "code",
+		),
+		latency: registry.Histogram(
+			"classifier_http_request_duration_seconds",
+			"HTTP request latency by path.",
+			metrics.ExponentialBuckets(0.0001, 4, 10),
+			"path",
+		),
+		scores: registry.Histogram(
+			"classifier_prediction_score",
+			"Distribution of predicted scores.",
+			metrics.LinearBuckets(0.1, 0.1, 10),
+		),
+		predictions: registry.Counter(
+			"classifier_predictions_total",
+			"Predictions served by label.",
+			"label",
+		),
+		positiveRate: registry.Gauge(
+			"classifier_positive_rate",
+			"Fraction of predictions served classified positive.",
+		),
+		version: registry.Gauge(
+			"classifier_model_version",
+			"Version of served model, incremented on each reload.",
+		),
+	}
+}
@@ -0,0 +1,274 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+func testParameters() lib.Parameters {
+	parameters := lib.NewParameters([]int{2, 1})
+	parameters.Weights[1] = *mat.NewDense(1, 2, []float64{1, 1})
+	parameters.Bias[1] = *mat.NewDense(1, 1, []float64{-1})
+	return parameters
+}
+
+func newTestServer(t *testing.T) *Server {
+	server, err := NewServer(func() (lib.Parameters, error) {
+		return testParameters(), nil
+	}, ServerOptions{InputWidth: 2})
+	assert.NoError(t, err)
+	return server
+}
+
+func request(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return recorder
+}
+
+func TestServerPredict(t *testing.T) {
+	server := newTestServer(t)
+
+	response := request(server, http.MethodPost, "/v1/predict", `{"features": [2, 2]}`)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"score": 0.9525741268224334, "label": 1}`, response.Body.String())
+}
+
+func TestServerPredictBatch(t *testing.T) {
+	server := newTestServer(t)
+
+	response := request(server, http.MethodPost, "/v1/predict/batch", `{"samples": [[2, 2], [0, 0]]}`)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"predictions": [
+		{"score": 0.9525741268224334, "label": 1},
+		{"score": 0.2689414213699951, "label": 0}
+	]}`, response.Body.String())
+}
+
+func TestServerPredictErrors(t *testing.T) {
+	server := newTestServer(t)
+
+	response := request(server, http.MethodPost, "/v1/predict", `{"features": [2]}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.JSONEq(t, `{"error": "sample 0 has 1 features, expected 2"}`, response.Body.String())
+
+	response = request(server, http.MethodPost, "/v1/predict", `{`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = request(server, http.MethodGet, "/v1/predict", ``)
+	assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+}
+
+func TestServerMaxBodyBytes(t *testing.T) {
+	server, err := NewServer(func() (lib.Parameters, error) {
+		return testParameters(), nil
+	}, ServerOptions{MaxBodyBytes: 32})
+	assert.NoError(t, err)
+
+	response := request(server, http.MethodPost, "/v1/predict", `{"features": [2, 2]}`)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	response = request(server, http.MethodPost, "/v1/predict/batch", `{"samples": [[2, 2], [2, 2], [2, 2], [2, 2]]}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.JSONEq(t, `{"error": "invalid JSON: http: request body too large"}`, response.Body.String())
+}
+
+func TestServerModel(t *testing.T) {
+	server := newTestServer(t)
+
+	response := request(server, http.MethodGet, "/v1/model", ``)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	var model modelResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &model))
+	assert.Equal(t, []int{2, 1}, model.Layers)
+	assert.Equal(t, []string{"", "sigmoid"}, model.Activations)
+	assert.Equal(t, 3, model.Parameters)
+	assert.Equal(t, 0.5, model.Threshold)
+	assert.Equal(t, 0, model.Version)
+}
+
+func TestServerHealth(t *testing.T) {
+	server := newTestServer(t)
+
+	assert.Equal(t, http.StatusOK, request(server, http.MethodGet, "/healthz", ``).Code)
+	assert.Equal(t, http.StatusOK, request(server, http.MethodGet, "/readyz", ``).Code)
+}
+
+func TestServerConcurrent(t *testing.T) {
+	server := httptest.NewServer(newTestServer(t))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := http.Post(server.URL+"/v1/predict", "application/json", strings.NewReader(`{"features": [2, 2]}`))
+			if assert.NoError(t, err) {
+				assert.Equal(t, http.StatusOK, response.StatusCode)
+				response.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestServerReload(t *testing.T) {
+	parameters := testParameters()
+	var loadErr error
+
+	server, err := NewServer(func() (lib.Parameters, error) {
+		return parameters, loadErr
+	}, ServerOptions{})
+	assert.NoError(t, err)
+
+	// swap to model with inverted weights
+	parameters = testParameters()
+	parameters.Weights[1] = *mat.NewDense(1, 2, []float64{-1, -1})
+
+	response := request(server.Admin(), http.MethodPost, "/admin/reload", ``)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"version": 1}`, response.Body.String())
+
+	response = request(server, http.MethodPost, "/v1/predict", `{"features": [2, 2]}`)
+	assert.JSONEq(t, `{"score": 0.0066928509242848554, "label": 0}`, response.Body.String())
+
+	// input width mismatch keeps current model
+	parameters = lib.NewParameters([]int{3, 1})
+
+	response = request(server.Admin(), http.MethodPost, "/admin/reload", ``)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.JSONEq(t, `{"error": "parameters input layer has 3 features, expected 2"}`, response.Body.String())
+
+	response = request(server, http.MethodPost, "/admin/reload", ``)
+	assert.Equal(t, http.StatusNotFound, response.Code, "reload is only served by admin handler")
+
+	loadErr = errors.New("missing file")
+	assert.EqualError(t, server.Reload(), "missing file")
+
+	assert.Equal(t, []int{2, 1}, server.Parameters().Layers)
+}
+
+func TestServerReloadConcurrent(t *testing.T) {
+	server := newTestServer(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, server.Reload())
+		}()
+		go func() {
+			defer wg.Done()
+			response := request(server, http.MethodPost, "/v1/predict", `{"features": [2, 2]}`)
+			assert.Equal(t, http.StatusOK, response.Code)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 20, server.current().version)
+}
+
+func TestServerBatching(t *testing.T) {
+	server, err := NewServer(func() (lib.Parameters, error) {
+		return testParameters(), nil
+	}, ServerOptions{MaxBatch: 8, MaxWait: time.Millisecond})
+	assert.NoError(t, err)
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response := request(server, http.MethodPost, "/v1/predict", `{"features": [2, 2]}`)
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.JSONEq(t, `{"score": 0.9525741268224334, "label": 1}`, response.Body.String())
+		}()
+	}
+	wg.Wait()
+
+	response := request(server, http.MethodPost, "/v1/predict", `{"features": [2]}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	server.Close()
+	response = request(server, http.MethodPost, "/v1/predict", `{"features": [2, 2]}`)
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+}
+
+func TestBatcherStatus(t *testing.T) {
+	assert.Equal(t, http.StatusServiceUnavailable, batcherStatus(ErrBatcherClosed))
+	assert.Equal(t, http.StatusServiceUnavailable, batcherStatus(context.DeadlineExceeded))
+	assert.Equal(t, statusClientClosedRequest, batcherStatus(context.Canceled))
+	assert.Equal(t, http.StatusBadRequest, batcherStatus(errors.New("sample 0 has 1 features, expected 2")))
+}
+
+func TestServerMetrics(t *testing.T) {
+	server := newTestServer(t)
+
+	request(server, http.MethodPost, "/v1/predict", `{"features": [2, 2]}`)
+	request(server, http.MethodPost, "/v1/predict/batch", `{"samples": [[2, 2], [0, 0], [0, 0], [0, 0]]}`)
+	request(server, http.MethodPost, "/v1/predict", `{"features": [2]}`)
+	request(server, http.MethodGet, "/unknown", ``)
+
+	response := request(server, http.MethodGet, "/metrics", ``)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	body := response.Body.String()
+	assert.Contains(t, body, `classifier_http_requests_total{path="/v1/predict",code="200"} 1`)
+	assert.Contains(t, body, `classifier_http_requests_total{path="/v1/predict",code="400"} 1`)
+	assert.Contains(t, body, `classifier_http_requests_total{path="/v1/predict/batch",code="200"} 1`)
+	assert.Contains(t, body, `classifier_http_requests_total{path="unmatched",code="404"} 1`)
+	assert.Contains(t, body, `classifier_http_request_duration_seconds_count{path="/v1/predict"} 2`)
+	assert.Contains(t, body, `classifier_prediction_score_count 5`)
+	assert.Contains(t, body, `classifier_predictions_total{label="0"} 3`)
+	assert.Contains(t, body, `classifier_predictions_total{label="1"} 2`)
+	assert.Contains(t, body, `classifier_positive_rate 0.4`)
+	assert.Contains(t, body, `classifier_model_version 0`)
+}
+
+func TestQuantizedServer(t *testing.T) {
+	data := *mat.NewDense(2, 2, []float64{2, 0, 2, 0})
+	quantized := lib.Quantize(testParameters(), data, true)
+
+	server, err := NewQuantizedServer(func() (lib.QuantizedParameters, error) {
+		return quantized, nil
+	}, ServerOptions{})
+	assert.NoError(t, err)
+
+	response := request(server, http.MethodPost, "/v1/predict", `{"features": [2, 2]}`)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var prediction lib.Prediction
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &prediction))
+	assert.Equal(t, 1, prediction.Label)
+	assert.InDelta(t, 0.9525741268224334, prediction.Score, 0.01)
+
+	response = request(server, http.MethodGet, "/v1/model", ``)
+	var model modelResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &model))
+	assert.Equal(t, []int{2, 1}, model.Layers)
+	assert.Equal(t, []string{"", "sigmoid"}, model.Activations)
+	assert.Equal(t, 3, model.Parameters)
+	assert.Equal(t, []int{2, 1}, server.Layers())
+
+	// input width mismatch keeps current model
+	quantized = lib.Quantize(lib.NewParameters([]int{3, 1}), *mat.NewDense(3, 1, []float64{1, 1, 1}), true)
+	assert.EqualError(t, server.Reload(), "quantized parameters input layer has 3 features, expected 2")
+	assert.Equal(t, []int{2, 1}, server.Layers())
+}
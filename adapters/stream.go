@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+const (
+	// CSV stream format of a record per sample
+	CSV = "csv"
+	// JSONLines stream format of a JSON value per line
+	JSONLines = "jsonl"
+)
+
+// SampleReader reads samples incrementally so streams of any size are classified in bounded memory
+type SampleReader struct {
+	format string
+	csv    *csv.Reader
+	lines  *bufio.Reader
+	record int
+	header []string
+}
+
+// Read up to n samples returning io.EOF once the stream is exhausted and no samples remain
+//
+// CSV records hold features per sample and a leading header record is skipped. JSON lines hold either an
+// array of features or an object with a "features" array, as accepted by the HTTP predict endpoint.
+func (r *SampleReader) Read(n int) ([][]float64, error) {
+	samples := make([][]float64, 0, n)
+
+	for len(samples) < n {
+		sample, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sample != nil {
+			samples = append(samples, sample)
+		}
+	}
+
+	if len(samples) == 0 {
+		return nil, io.EOF
+	}
+
+	return samples, nil
+}
+
+// Header of CSV stream, nil until read or when the stream has no header
+func (r *SampleReader) Header() []string {
+	return r.header
+}
+
+// next sample of stream or nil when a record is skipped, only a CSV header or a blank JSON line is skipped
+func (r *SampleReader) next() ([]float64, error) {
+	r.record++
+
+	if r.format == CSV {
+		record, err := r.csv.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		sample, err := parseFloats(record)
+		if err != nil && r.record == 1 {
+			r.header = append([]string(nil), record...)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sample record %d: %s", r.record, err)
+		}
+		return sample, nil
+	}
+
+	line, err := r.lines.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		return nil, err
+	}
+
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, nil
+	}
+
+	var sample []float64
+	if line[0] == '[' {
+		err = json.Unmarshal(line, &sample)
+	} else {
+		var request predictRequest
+		err = json.Unmarshal(line, &request)
+		sample = request.Features
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sample line %d: %s", r.record, err)
+	}
+	if sample == nil {
+		return nil, fmt.Errorf("sample line %d: missing features", r.record)
+	}
+
+	return sample, nil
+}
+
+// PredictionWriter writes predictions in the format samples were read
+type PredictionWriter struct {
+	format  string
+	csv     *csv.Writer
+	lines   *bufio.Writer
+	encoder *json.Encoder
+}
+
+// Write predictions in order, CSV output begins with a "score,label" header
+func (w *PredictionWriter) Write(predictions []lib.Prediction) error {
+	if w.format == CSV {
+		for _, prediction := range predictions {
+			w.csv.Write([]string{
+				strconv.FormatFloat(prediction.Score, 'g', -1, 64),
+				strconv.Itoa(prediction.Label),
+			})
+		}
+		return w.csv.Error()
+	}
+
+	for _, prediction := range predictions {
+		if err := w.encoder.Encode(prediction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush buffered predictions to the underlying writer
+func (w *PredictionWriter) Flush() error {
+	if w.format == CSV {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return w.lines.Flush()
+}
+
+// NewSampleReader adapter reading samples from r in CSV or JSON lines format
+func NewSampleReader(r io.Reader, format string) (*SampleReader, error) {
+	switch format {
+	case CSV:
+		reader := csv.NewReader(r)
+		reader.ReuseRecord = true
+		return &SampleReader{format: format, csv: reader}, nil
+	case JSONLines:
+		return &SampleReader{format: format, lines: bufio.NewReader(r)}, nil
+	}
+	return nil, fmt.Errorf("unsupported stream format %q", format)
+}
+
+// NewPredictionWriter adapter writing predictions to w in CSV or JSON lines format
+func NewPredictionWriter(w io.Writer, format string) (*PredictionWriter, error) {
+	switch format {
+	case CSV:
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"score", "label"})
+		return &PredictionWriter{format: format, csv: writer}, nil
+	case JSONLines:
+		lines := bufio.NewWriter(w)
+		return &PredictionWriter{format: format, lines: lines, encoder: json.NewEncoder(lines)}, nil
+	}
+	return nil, fmt.Errorf("unsupported stream format %q", format)
+}
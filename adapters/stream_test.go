@@ -0,0 +1,88 @@
+package adapters
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+func readAll(t *testing.T, reader *SampleReader, n int) [][][]float64 {
+	var chunks [][][]float64
+	for {
+		samples, err := reader.Read(n)
+		if err == io.EOF {
+			return chunks
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, samples)
+	}
+}
+
+func TestSampleReaderCSV(t *testing.T) {
+	reader, err := NewSampleReader(strings.NewReader("a,b\n1,2\n3,4\n5,6\n"), CSV)
+	assert.NoError(t, err)
+
+	chunks := readAll(t, reader, 2)
+
+	assert.Equal(t, [][][]float64{{{1, 2}, {3, 4}}, {{5, 6}}}, chunks)
+	assert.Equal(t, []string{"a", "b"}, reader.Header())
+}
+
+func TestSampleReaderCSVInvalid(t *testing.T) {
+	reader, err := NewSampleReader(strings.NewReader("1,2\nx,4\n"), CSV)
+	assert.NoError(t, err)
+
+	_, err = reader.Read(2)
+
+	assert.EqualError(t, err, "sample record 2: strconv.ParseFloat: parsing \"x\": invalid syntax")
+}
+
+func TestSampleReaderJSONLines(t *testing.T) {
+	reader, err := NewSampleReader(strings.NewReader("[1,2]\n\n{\"features\":[3,4]}\n[5,6]"), JSONLines)
+	assert.NoError(t, err)
+
+	chunks := readAll(t, reader, 2)
+
+	assert.Equal(t, [][][]float64{{{1, 2}, {3, 4}}, {{5, 6}}}, chunks)
+}
+
+func TestSampleReaderJSONLinesMissingFeatures(t *testing.T) {
+	for _, line := range []string{"{}", "null", `{"feature":[3,4]}`, `{"features":null}`} {
+		reader, err := NewSampleReader(strings.NewReader("[1,2]\n"+line+"\n[5,6]\n"), JSONLines)
+		assert.NoError(t, err)
+
+		_, err = reader.Read(3)
+		assert.EqualError(t, err, "sample line 2: missing features", line)
+	}
+}
+
+func TestSampleReaderUnsupported(t *testing.T) {
+	_, err := NewSampleReader(strings.NewReader(""), "xml")
+
+	assert.EqualError(t, err, "unsupported stream format \"xml\"")
+}
+
+func TestPredictionWriter(t *testing.T) {
+	predictions := []lib.Prediction{{Score: 0.25, Label: 0}, {Score: 0.75, Label: 1}}
+
+	cases := map[string]string{
+		CSV:       "score,label\n0.25,0\n0.75,1\n",
+		JSONLines: "{\"score\":0.25,\"label\":0}\n{\"score\":0.75,\"label\":1}\n",
+	}
+
+	for format, expected := range cases {
+		var out bytes.Buffer
+		writer, err := NewPredictionWriter(&out, format)
+		assert.NoError(t, err)
+
+		assert.NoError(t, writer.Write(predictions))
+		assert.NoError(t, writer.Flush())
+
+		assert.Equal(t, expected, out.String(), format)
+	}
+}
@@ -0,0 +1,35 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch file at path polling every interval and calling changed when its size or modification time changes
+//
+// Watch blocks until ctx is cancelled.
+func Watch(ctx context.Context, path string, interval time.Duration, changed func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous, _ := os.Stat(path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if previous == nil || !current.ModTime().Equal(previous.ModTime()) || current.Size() != previous.Size() {
+			previous = current
+			changed()
+		}
+	}
+}
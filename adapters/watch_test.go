@@ -0,0 +1,50 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := os.MkdirTemp("", "watch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "parameters.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("a"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan struct{}, 1)
+
+	done := make(chan struct{})
+	go func() {
+		Watch(ctx, path, 5*time.Millisecond, func() {
+			changed <- struct{}{}
+		})
+		close(done)
+	}()
+
+	timeout := time.After(time.Second)
+	contents := []byte("a")
+
+	for detected := false; !detected; {
+		contents = append(contents, 'b')
+		assert.NoError(t, os.WriteFile(path, contents, 0644))
+
+		select {
+		case <-changed:
+			detected = true
+		case <-time.After(20 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("change not detected")
+		}
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/bench"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// benchmark of model printed by bench
+type benchmark struct {
+	Path      string         `json:"path"`
+	Layers    []int          `json:"layers"`
+	CPUs      int            `json:"cpus"`
+	Float32   bool           `json:"float32"`
+	Quantized bool           `json:"quantized"`
+	Results   []bench.Result `json:"results"`
+}
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark predictions of trained parameters",
+	Long:  "Benchmark prediction throughput in samples per second and batch latency percentiles of trained parameters on the local machine",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := resolveParameters(viper.GetString("parameters"))
+		log.FailOnError(err, "failed to resolve parameters")
+
+		store := adapters.NewStore(path)
+
+		var (
+			layers  []int
+			predict bench.Predict
+		)
+		if quantizedPath := viper.GetString("quantized"); quantizedPath != "" {
+			quantized, err := store.ReadQuantized(quantizedPath)
+			log.FailOnError(err, "failed to load quantized parameters")
+
+			path, layers = quantizedPath, quantized.Layers
+			predict = quantized.Predict
+		} else {
+			parameters, err := store.ReadParameters()
+			log.FailOnError(err, "failed to load parameters")
+
+			path, layers = store.Path(), parameters.Layers
+			predict = func(data mat.Dense) mat.Dense {
+				return lib.Predict(parameters, data)
+			}
+			if viper.GetBool("float32") {
+				// converted once so timed predictions only compute in single precision
+				predict = lib.NewParameters32(parameters).Predict
+			}
+		}
+
+		var data mat.Dense
+		if dataPath := viper.GetString("data"); dataPath != "" {
+			data, _, err = store.ReadData(dataPath)
+			log.FailOnError(err, "failed to load data")
+			if features, _ := data.Dims(); features != layers[0] {
+				log.FailOnError(fmt.Errorf("parameters input layer has %d features, expected %d", layers[0], features), "failed to benchmark data")
+			}
+		} else {
+			// random samples when no data is given, predictions cost the same regardless of values
+			features, samples := layers[0], viper.GetInt("random_samples")
+			random := rand.New(rand.NewSource(1))
+			data = *mat.NewDense(features, samples, nil)
+			for i := 0; i < features; i++ {
+				for j := 0; j < samples; j++ {
+					data.Set(i, j, random.NormFloat64())
+				}
+			}
+		}
+
+		res := benchmark{
+			Path:      path,
+			Layers:    layers,
+			CPUs:      runtime.NumCPU(),
+			Float32:   viper.GetBool("float32") && viper.GetString("quantized") == "",
+			Quantized: viper.GetString("quantized") != "",
+		}
+
+		for _, batchSize := range intSlice(viper.GetStringSlice("batch_size")) {
+			result, err := bench.Run(predict, data, batchSize, viper.GetDuration("duration"), viper.GetInt("warmup"))
+			log.FailOnError(err, "failed to benchmark parameters")
+			res.Results = append(res.Results, result)
+		}
+
+		if viper.GetBool("json") {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			log.FailOnError(encoder.Encode(res), "failed to write benchmark")
+			return
+		}
+
+		log.FailOnError(res.text(os.Stdout), "failed to write benchmark")
+	},
+}
+
+// text writes a human readable benchmark
+func (b benchmark) text(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "path:\t%s\n", b.Path)
+	fmt.Fprintf(tw, "layers:\t%v\n", b.Layers)
+	fmt.Fprintf(tw, "cpus:\t%d\n", b.CPUs)
+	fmt.Fprintf(tw, "float32:\t%t\n", b.Float32)
+	fmt.Fprintf(tw, "quantized:\t%t\n", b.Quantized)
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "batch size\tbatches\tsamples/s\tmean\tp50\tp90\tp99\tmax")
+	for _, r := range b.Results {
+		fmt.Fprintf(tw, "%d\t%d\t%.0f\t%s\t%s\t%s\t%s\t%s\n",
+			r.BatchSize, r.Batches, r.SamplesPerSecond,
+			r.Latency.Mean, r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max,
+		)
+	}
+
+	return tw.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().String("parameters", "parameters.csv", "trained parameters path or registry model reference (name@stage or name@version)")
+	benchCmd.Flags().String("data", "", "data CSV with label as last column whose samples are predicted (default random samples)")
+	benchCmd.Flags().Int("random_samples", 4096, "random samples generated when no data is given")
+	benchCmd.Flags().StringSlice("batch_size", []string{"1", "32", "256"}, "samples per prediction, a benchmark is run for each")
+	benchCmd.Flags().Duration("duration", 2*time.Second, "time each batch size is benchmarked for")
+	benchCmd.Flags().Int("warmup", 10, "batches predicted before timing")
+	benchCmd.Flags().Bool("float32", false, "predict computing in single precision")
+	benchCmd.Flags().String("quantized", "", "int8 quantized parameters path predicted instead of parameters")
+	benchCmd.Flags().Bool("json", false, "write benchmark as JSON")
+}
@@ -1,29 +1,106 @@
 package commands
 
 import (
+	"bufio"
+	"io"
+	"os"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
+	"github.com/gregl83/go-binary-classify-nn/adapters"
 	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+	"github.com/gregl83/go-binary-classify-nn/lib"
 )
 
 // classifyCmd represents the classify command
 var classifyCmd = &cobra.Command{
 	Use:   "classify",
 	Short: "Classify input",
-	Long: "Classify input using trained neural network",
+	Long: "Classify input using trained neural network, reading samples from stdin when no data path is given",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Logger.Info("classifying input")
 
-		// todo - load trained weights
+		path, err := resolveParameters(viper.GetString("parameters"))
+		log.FailOnError(err, "failed to resolve parameters")
+
+		store := adapters.NewStore(path)
+
+		var classify func(samples [][]float64) ([]lib.Prediction, error)
+		if quantizedPath := viper.GetString("quantized"); quantizedPath != "" {
+			quantized, err := store.ReadQuantized(quantizedPath)
+			log.FailOnError(err, "failed to load quantized parameters")
+			classify = quantized.Classify
+		} else {
+			parameters, err := store.ReadParameters()
+			log.FailOnError(err, "failed to load parameters")
+			classify = func(samples [][]float64) ([]lib.Prediction, error) {
+				return lib.Classify(parameters, samples)
+			}
+		}
+
+		metadata, err := store.ReadMetadata()
+		if os.IsNotExist(err) {
+			log.Logger.WithField("path", store.MetadataPath()).Warn("parameters have no metadata, feature names not checked")
+			err = nil
+		}
+		log.FailOnError(err, "failed to load metadata")
+
+		var input io.Reader = os.Stdin
+		if path := viper.GetString("data"); path != "" && path != "-" {
+			file, err := os.Open(path)
+			log.FailOnError(err, "failed to load data")
+			defer file.Close()
+			input = file
+		}
+
+		format := viper.GetString("format")
+
+		reader, err := adapters.NewSampleReader(bufio.NewReader(input), format)
+		log.FailOnError(err, "failed to read data")
 
-		// todo - load data
+		writer, err := adapters.NewPredictionWriter(os.Stdout, format)
+		log.FailOnError(err, "failed to write predictions")
 
-		// todo - run classify
+		chunkSize := viper.GetInt("chunk_size")
+		if chunkSize < 1 {
+			chunkSize = 1
+		}
 
-		log.Logger.Info("classification completed")
+		classified := 0
+		for {
+			samples, err := reader.Read(chunkSize)
+			if err == io.EOF {
+				break
+			}
+			log.FailOnError(err, "failed to load data")
+
+			if classified == 0 {
+				log.FailOnError(metadata.CheckFeatures(reader.Header()), "input features do not match model")
+			}
+
+			predictions, err := classify(samples)
+			log.FailOnError(err, "failed to classify data")
+
+			log.FailOnError(writer.Write(predictions), "failed to write predictions")
+			classified += len(predictions)
+		}
+
+		log.FailOnError(writer.Flush(), "failed to write predictions")
+
+		log.Logger.WithField("samples", classified).Info("classification completed")
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(classifyCmd)
-}
\ No newline at end of file
+
+	classifyCmd.Flags().String("parameters", "parameters.csv", "trained parameters path or registry model reference (name@stage or name@version)")
+	classifyCmd.Flags().String("quantized", "", "int8 quantized parameters path classified with instead of parameters")
+	classifyCmd.Flags().String("data", "", "samples path, stdin when empty or -")
+	classifyCmd.Flags().String("format", adapters.CSV, "samples and predictions format (csv or jsonl)")
+	classifyCmd.Flags().Int("chunk_size", 1024, "samples classified per forward pass, bounds memory when streaming")
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/codegen"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+)
+
+// codegenCmd represents the codegen command
+var codegenCmd = &cobra.Command{
+	Use:   "codegen",
+	Short: "Generate standalone Go source for trained parameters",
+	Long:  "Generate a Go package predicting with trained parameters using only the standard library, along with a test of its predictions",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := resolveParameters(viper.GetString("parameters"))
+		log.FailOnError(err, "failed to resolve parameters")
+
+		store := adapters.NewStore(path)
+
+		parameters, err := store.ReadParameters()
+		log.FailOnError(err, "failed to load parameters")
+
+		source := codegen.Source{Package: viper.GetString("package"), Parameters: parameters}
+		features, count := parameters.Layers[0], viper.GetInt("samples")
+
+		if path := viper.GetString("data"); path != "" {
+			data, _, err := store.ReadData(path)
+			log.FailOnError(err, "failed to load data")
+
+			_, samples := data.Dims()
+			for j := 0; j < samples && j < count; j++ {
+				source.Samples = append(source.Samples, mat.Col(nil, j, &data))
+			}
+		}
+
+		// random samples when no data is given, seeded so generated tests are reproducible
+		random := rand.New(rand.NewSource(1))
+		for len(source.Samples) < count {
+			sample := make([]float64, features)
+			for i := range sample {
+				sample[i] = random.Float64()*2 - 1
+			}
+			source.Samples = append(source.Samples, sample)
+		}
+
+		dir := viper.GetString("out_dir")
+		log.FailOnError(os.MkdirAll(dir, 0755), "failed to create output directory")
+
+		files := map[string]func(f *os.File) error{
+			"model.go":      func(f *os.File) error { return source.Go(f) },
+			"model_test.go": func(f *os.File) error { return source.Test(f) },
+		}
+
+		for name, write := range files {
+			file, err := os.Create(filepath.Join(dir, name))
+			log.FailOnError(err, "failed to create source")
+			log.FailOnError(write(file), "failed to generate source")
+			log.FailOnError(file.Close(), "failed to write source")
+		}
+
+		log.Logger.WithField("path", dir).WithField("package", source.Package).Info("source generated")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(codegenCmd)
+
+	codegenCmd.Flags().String("parameters", "parameters.csv", "trained parameters path or registry model reference (name@stage or name@version)")
+	codegenCmd.Flags().String("package", "model", "package name of generated source")
+	codegenCmd.Flags().String("out_dir", "model", "directory model.go and model_test.go are written to")
+	codegenCmd.Flags().String("data", "", "data CSV with label as last column whose samples the generated test uses (default random samples)")
+	codegenCmd.Flags().Int("samples", 5, "samples the generated test checks predictions of")
+}
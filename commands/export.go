@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export trained parameters to ONNX",
+	Long:  "Export trained parameters as an ONNX model of Gemm and activation nodes for ONNX Runtime",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := resolveParameters(viper.GetString("parameters"))
+		log.FailOnError(err, "failed to resolve parameters")
+
+		store := adapters.NewStore(path)
+
+		parameters, err := store.ReadParameters()
+		log.FailOnError(err, "failed to load parameters")
+
+		log.FailOnError(store.CreateONNX(viper.GetString("out"), parameters), "failed to export parameters")
+
+		log.Logger.WithField("path", viper.GetString("out")).Info("parameters exported")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().String("parameters", "parameters.csv", "trained parameters path or registry model reference (name@stage or name@version)")
+	exportCmd.Flags().String("out", "model.onnx", "path ONNX model is written to")
+}
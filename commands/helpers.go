@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+)
+
+// intSlice converts flag or env values, possibly comma separated, to integers (dies)
+func intSlice(values []string) []int {
+	var res []int
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			i, err := strconv.Atoi(strings.TrimSpace(field))
+			log.FailOnError(err, "invalid integer")
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// resolveParameters path of a parameters file or registry model reference (name@stage or name@version)
+func resolveParameters(ref string) (string, error) {
+	if !isModelReference(ref) {
+		return ref, nil
+	}
+	return adapters.NewRegistry(viper.GetString("registry")).Resolve(ref)
+}
+
+// isModelReference when ref names a registry model rather than an existing parameters file
+func isModelReference(ref string) bool {
+	_, err := os.Stat(ref)
+	return err != nil && strings.Contains(ref, "@")
+}
@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// training metadata of inspected model
+type training struct {
+	Config  map[string]interface{} `json:"config"`
+	Epochs  int                    `json:"epochs"`
+	Metrics lib.Metrics            `json:"metrics"`
+}
+
+// inspection of model printed by inspect, threshold and scaler are those stored in metadata and null when
+// not stored
+type inspection struct {
+	Path      string             `json:"path"`
+	Model     lib.Inspection     `json:"model"`
+	Threshold *float64           `json:"threshold"`
+	Scaler    *adapters.Scaler   `json:"scaler"`
+	Metadata  *adapters.Metadata `json:"metadata,omitempty"`
+	Training  *training          `json:"training,omitempty"`
+}
+
+// inspectCmd represents the inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Inspect trained parameters",
+	Long:  "Inspect layers, activations, parameter counts and statistics of trained parameters with their metadata and optional training history",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := resolveParameters(viper.GetString("parameters"))
+		log.FailOnError(err, "failed to resolve parameters")
+
+		store := adapters.NewStore(path)
+
+		parameters, err := store.ReadParameters()
+		log.FailOnError(err, "failed to load parameters")
+
+		res := inspection{Path: store.Path(), Model: lib.Inspect(parameters)}
+
+		if metadata, err := store.ReadMetadata(); err == nil {
+			res.Metadata, res.Threshold, res.Scaler = &metadata, metadata.Threshold, metadata.Scaler
+		} else if !os.IsNotExist(err) {
+			log.FailOnError(err, "failed to load metadata")
+		}
+
+		if path := viper.GetString("data"); path != "" {
+			data, _, err := store.ReadData(path)
+			log.FailOnError(err, "failed to load data")
+			features, _ := data.Dims()
+			log.FailOnError(parameters.Validate(features), "failed to inspect data")
+			res.Model.DeadUnits(parameters, data)
+		}
+
+		if path := viper.GetString("history"); path != "" {
+			config, history, err := store.ReadHistory(path)
+			log.FailOnError(err, "failed to load history")
+
+			res.Training = &training{Config: config, Epochs: len(history.Epochs), Metrics: lib.Metrics{}}
+			for name, values := range history.Metrics {
+				if len(values) > 0 {
+					res.Training.Metrics[name] = values[len(values)-1]
+				}
+			}
+		}
+
+		if viper.GetBool("json") {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			log.FailOnError(encoder.Encode(res), "failed to write inspection")
+			return
+		}
+
+		log.FailOnError(res.text(os.Stdout), "failed to write inspection")
+	},
+}
+
+// text writes a human readable inspection
+func (i inspection) text(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "path:\t%s\n", i.Path)
+	fmt.Fprintf(tw, "layers:\t%v\n", i.Model.Layers)
+	fmt.Fprintf(tw, "parameters:\t%d\n", i.Model.Parameters)
+	fmt.Fprintf(tw, "threshold:\t%s\n", i.threshold())
+	fmt.Fprintf(tw, "scaler:\t%s\n", i.scaler())
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "layer\tunits\tactivation\tparameters\tweight mean\tweight std\tweight min\tweight max\tbias mean\tbias std\tbias min\tbias max\tdead")
+	for _, s := range i.Model.Summaries {
+		dead := "-"
+		if s.DeadFraction != nil {
+			dead = fmt.Sprintf("%.2f", *s.DeadFraction)
+		}
+		activation := s.Activation
+		if s.BatchNorm {
+			activation = "batchnorm+" + activation
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%d\t%.4g\t%.4g\t%.4g\t%.4g\t%.4g\t%.4g\t%.4g\t%.4g\t%s\n",
+			s.Layer, s.Units, activation, s.Parameters,
+			s.Weights.Mean, s.Weights.Std, s.Weights.Min, s.Weights.Max,
+			s.Bias.Mean, s.Bias.Std, s.Bias.Min, s.Bias.Max,
+			dead,
+		)
+	}
+
+	if i.Metadata != nil {
+		fmt.Fprintln(tw)
+		fmt.Fprintf(tw, "created at:\t%s\n", i.Metadata.CreatedAt.Format(time.RFC3339))
+		fmt.Fprintf(tw, "version:\t%s\n", i.Metadata.Version)
+		fmt.Fprintf(tw, "dataset hash:\t%s\n", i.Metadata.DatasetHash)
+		fmt.Fprintf(tw, "features:\t%s\n", strings.Join(i.Metadata.Features, ","))
+		fmt.Fprintf(tw, "notes:\t%s\n", i.Metadata.Notes)
+	}
+
+	if i.Training != nil {
+		fmt.Fprintln(tw)
+		fmt.Fprintf(tw, "epochs:\t%d\n", i.Training.Epochs)
+
+		var names []string
+		for name := range i.Training.Config {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(tw, "%s:\t%v\n", name, i.Training.Config[name])
+		}
+
+		names = names[:0]
+		for name := range i.Training.Metrics {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(tw, "final %s:\t%.4g\n", name, i.Training.Metrics[name])
+		}
+	}
+
+	return tw.Flush()
+}
+
+// threshold stored with model or "not stored"
+func (i inspection) threshold() string {
+	if i.Threshold == nil {
+		return "not stored"
+	}
+	return fmt.Sprintf("%g", *i.Threshold)
+}
+
+// scaler stored with model or "not stored"
+func (i inspection) scaler() string {
+	if i.Scaler == nil {
+		return "not stored"
+	}
+	return fmt.Sprintf("mean %v, scale %v", i.Scaler.Mean, i.Scaler.Scale)
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+
+	inspectCmd.Flags().String("parameters", "parameters.csv", "trained parameters path or registry model reference (name@stage or name@version)")
+	inspectCmd.Flags().String("history", "", "training history JSON path for training metadata")
+	inspectCmd.Flags().String("data", "", "data CSV with label as last column used to measure dead relu units")
+	inspectCmd.Flags().Bool("json", false, "write inspection as JSON")
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+)
+
+// modelsCmd represents the models command
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Manage model registry",
+	Long:  "Manage versioned models and their staging and production stages in the local model registry",
+}
+
+// modelsListCmd represents the models list command
+var modelsListCmd = &cobra.Command{
+	Use:   "list [name]",
+	Short: "List registered models",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		versions, err := adapters.NewRegistry(viper.GetString("registry")).List()
+		log.FailOnError(err, "failed to list models")
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tVERSION\tSTAGES\tREGISTERED\tSOURCE")
+		for _, version := range versions {
+			if len(args) > 0 && version.Name != args[0] {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n",
+				version.Name,
+				version.Version,
+				strings.Join(version.Stages, ","),
+				version.RegisteredAt.Format(time.RFC3339),
+				version.Source,
+			)
+		}
+		log.FailOnError(w.Flush(), "failed to list models")
+	},
+}
+
+// modelsRegisterCmd represents the models register command
+var modelsRegisterCmd = &cobra.Command{
+	Use:   "register <name> <parameters>",
+	Short: "Register parameters as the next version of a model",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := adapters.NewRegistry(viper.GetString("registry")).Register(args[0], args[1])
+		log.FailOnError(err, "failed to register model")
+
+		log.Logger.WithField("name", version.Name).WithField("version", version.Version).WithField("path", version.Path).Info("model registered")
+	},
+}
+
+// modelsPromoteCmd represents the models promote command
+var modelsPromoteCmd = &cobra.Command{
+	Use:   "promote <name> <version> <staging|production>",
+	Short: "Promote a model version to a stage",
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.Atoi(strings.TrimPrefix(args[1], "v"))
+		log.FailOnError(err, "invalid version")
+
+		err = adapters.NewRegistry(viper.GetString("registry")).Promote(args[0], version, args[2])
+		log.FailOnError(err, "failed to promote model")
+
+		log.Logger.WithField("name", args[0]).WithField("version", version).WithField("stage", args[2]).Info("model promoted")
+	},
+}
+
+// modelsRollbackCmd represents the models rollback command
+var modelsRollbackCmd = &cobra.Command{
+	Use:   "rollback <name> <staging|production>",
+	Short: "Roll a stage back to its previously promoted version",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := adapters.NewRegistry(viper.GetString("registry")).Rollback(args[0], args[1])
+		log.FailOnError(err, "failed to rollback model")
+
+		log.Logger.WithField("name", args[0]).WithField("version", version).WithField("stage", args[1]).Info("model rolled back")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+
+	modelsCmd.AddCommand(modelsListCmd, modelsRegisterCmd, modelsPromoteCmd, modelsRollbackCmd)
+}
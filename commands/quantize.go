@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// quantizeCmd represents the quantize command
+var quantizeCmd = &cobra.Command{
+	Use:   "quantize",
+	Short: "Quantize trained parameters to int8",
+	Long:  "Quantize trained parameters to int8 weights calibrated on sample data and compare quantized to float predictions",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := resolveParameters(viper.GetString("parameters"))
+		log.FailOnError(err, "failed to resolve parameters")
+
+		store := adapters.NewStore(path)
+
+		parameters, err := store.ReadParameters()
+		log.FailOnError(err, "failed to load parameters")
+
+		data, labels, err := store.ReadData(viper.GetString("data"))
+		log.FailOnError(err, "failed to load calibration data")
+
+		features, _ := data.Dims()
+		log.FailOnError(parameters.Validate(features), "failed to quantize parameters")
+
+		quantized := lib.Quantize(parameters, data, viper.GetBool("per_row"))
+
+		if path := viper.GetString("validation"); path != "" {
+			data, labels, err = store.ReadData(path)
+			log.FailOnError(err, "failed to load validation data")
+
+			features, _ := data.Dims()
+			log.FailOnError(parameters.Validate(features), "failed to compare quantized parameters")
+		}
+
+		comparison := lib.CompareQuantized(parameters, quantized, data, labels)
+
+		log.FailOnError(store.CreateQuantized(viper.GetString("out"), quantized), "failed to save quantized parameters")
+
+		if path := viper.GetString("comparison"); path != "" {
+			log.FailOnError(store.CreateComparison(path, comparison), "failed to save comparison")
+		}
+
+		log.Logger.
+			WithField("path", viper.GetString("out")).
+			WithField("float_accuracy", comparison.Float.Accuracy).
+			WithField("quantized_accuracy", comparison.Quantized.Accuracy).
+			WithField("float_roc_auc", comparison.Float.ROCAUC).
+			WithField("quantized_roc_auc", comparison.Quantized.ROCAUC).
+			WithField("max_score_error", comparison.MaxScoreError).
+			WithField("agreement", comparison.Agreement).
+			Info("parameters quantized")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(quantizeCmd)
+
+	quantizeCmd.Flags().String("parameters", "parameters.csv", "trained parameters path or registry model reference (name@stage or name@version)")
+	quantizeCmd.Flags().String("data", "", "calibration data CSV with label as last column")
+	quantizeCmd.Flags().String("validation", "", "data CSV with label as last column quantized and float predictions are compared on (default calibration data)")
+	quantizeCmd.Flags().Bool("per_row", true, "scale weights per unit rather than per layer")
+	quantizeCmd.Flags().String("out", "parameters.int8.csv", "path quantized parameters are written to")
+	quantizeCmd.Flags().String("comparison", "", "path comparison of quantized and float evaluations JSON is written to")
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/report"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate experiment report",
+	Long:  "Generate a self-contained HTML experiment report from saved parameters, training history and evaluation",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Logger.Info("generating report")
+
+		store := adapters.NewStore(viper.GetString("parameters"))
+
+		parameters, err := store.ReadParameters()
+		log.FailOnError(err, "failed to load parameters")
+
+		config, history, err := store.ReadHistory(viper.GetString("history"))
+		log.FailOnError(err, "failed to load history")
+
+		evaluation, err := store.ReadEvaluation(viper.GetString("evaluation"))
+		log.FailOnError(err, "failed to load evaluation")
+
+		file, err := os.Create(viper.GetString("out"))
+		log.FailOnError(err, "failed to create report")
+		defer file.Close()
+
+		log.FailOnError(report.Report{
+			Title:      viper.GetString("title"),
+			Config:     config,
+			Parameters: parameters,
+			History:    history,
+			Evaluation: evaluation,
+		}.HTML(file), "failed to write report")
+
+		log.Logger.WithField("path", file.Name()).Info("report completed")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().String("parameters", "parameters.csv", "trained parameters path")
+	reportCmd.Flags().String("history", "history.json", "training history JSON path")
+	reportCmd.Flags().String("evaluation", "evaluation.json", "evaluation JSON path")
+	reportCmd.Flags().String("out", "report.html", "path HTML report is written to")
+	reportCmd.Flags().String("title", "Experiment Report", "report title")
+}
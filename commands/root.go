@@ -10,11 +10,15 @@ import (
 
 var cfgFile string
 
+// Version of tool, set at build time with -ldflags "-X github.com/gregl83/go-binary-classify-nn/commands.Version=..."
+var Version = "dev"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "go-binary-classify-nn",
-	Short: "Binary classification neural network",
-	Long: `Use this tool to train and run a binary classification neural network`,
+	Use:     "go-binary-classify-nn",
+	Short:   "Binary classification neural network",
+	Long:    `Use this tool to train and run a binary classification neural network`,
+	Version: Version,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -32,6 +36,14 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.go-binary-classify-nn.yaml)")
+	rootCmd.PersistentFlags().String("registry", "models", "model registry root directory")
+	rootCmd.PersistentFlags().String("log_level", "debug", "log level (panic, fatal, error, warn, info, debug)")
+	rootCmd.PersistentFlags().String("log_format", "text", "log format (text or json)")
+	rootCmd.PersistentFlags().String("log_file", "", "log file path (default is stderr)")
+	rootCmd.PersistentFlags().Int64("log_max_size", 100, "log file size in megabytes before rotating, 0 disables rotation")
+	rootCmd.PersistentFlags().Int("log_max_backups", 5, "rotated log files kept")
+
+	viper.BindPFlags(rootCmd.PersistentFlags())
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -54,7 +66,17 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
+	err := viper.ReadInConfig()
+
+	log.FailOnError(log.Configure(log.Options{
+		Level:      viper.GetString("log_level"),
+		Format:     viper.GetString("log_format"),
+		File:       viper.GetString("log_file"),
+		MaxSize:    viper.GetInt64("log_max_size") * 1024 * 1024,
+		MaxBackups: viper.GetInt("log_max_backups"),
+	}), "invalid log configuration")
+
+	if err == nil {
 		log.Logger.Debug("Using config file:", viper.ConfigFileUsed())
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+
+	"github.com/gregl83/go-binary-classify-nn/adapters"
+	"github.com/gregl83/go-binary-classify-nn/adapters/classifierpb"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve classification over HTTP and gRPC",
+	Long:  "Serve classification of samples using trained neural network over HTTP JSON endpoints and optionally gRPC",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := viper.GetString("parameters")
+
+		// model references are resolved on every load so promotions and rollbacks are served on reload
+		load := func() (lib.Parameters, error) {
+			path, err := resolveParameters(ref)
+			if err != nil {
+				return lib.Parameters{}, err
+			}
+			return adapters.NewStore(path).ReadParameters()
+		}
+
+		watched := ref
+		if isModelReference(ref) {
+			watched = adapters.NewRegistry(viper.GetString("registry")).IndexPath(strings.SplitN(ref, "@", 2)[0])
+		}
+
+		options := adapters.ServerOptions{
+			InputWidth:   viper.GetInt("input_width"),
+			MaxBatch:     viper.GetInt("max_batch"),
+			MaxWait:      viper.GetDuration("max_wait"),
+			MaxBodyBytes: viper.GetInt64("max_body_bytes"),
+		}
+
+		var (
+			classifier *adapters.Server
+			err        error
+		)
+		if quantizedPath := viper.GetString("quantized"); quantizedPath != "" {
+			ref, watched = quantizedPath, quantizedPath
+			classifier, err = adapters.NewQuantizedServer(func() (lib.QuantizedParameters, error) {
+				return adapters.NewStore(quantizedPath).ReadQuantized(quantizedPath)
+			}, options)
+		} else {
+			classifier, err = adapters.NewServer(load, options)
+		}
+		log.FailOnError(err, "failed to load parameters")
+		defer classifier.Close()
+
+		server := &http.Server{
+			Addr:    viper.GetString("addr"),
+			Handler: classifier,
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		if interval := viper.GetDuration("watch_interval"); interval > 0 {
+			go adapters.Watch(ctx, watched, interval, func() {
+				if err := classifier.Reload(); err != nil {
+					log.Logger.WithField("parameters", ref).WithError(err).Error("failed to reload parameters")
+					return
+				}
+				log.Logger.WithField("parameters", ref).WithField("layers", classifier.Layers()).Info("parameters reloaded")
+			})
+		}
+
+		var grpcServer *grpc.Server
+		if viper.GetBool("grpc") {
+			listener, err := net.Listen("tcp", viper.GetString("grpc_addr"))
+			log.FailOnError(err, "failed to listen for gRPC")
+
+			grpcServer = grpc.NewServer()
+			classifierpb.RegisterClassifierServer(grpcServer, adapters.NewGRPCService(classifier))
+
+			go func() {
+				log.Logger.WithField("addr", listener.Addr().String()).Info("serving neural network over gRPC")
+				if err := grpcServer.Serve(listener); err != nil {
+					log.FailOnError(err, "gRPC server failed")
+				}
+			}()
+		}
+
+		// reloading is served on its own listener as it is not authenticated
+		var adminServer *http.Server
+		if addr := viper.GetString("admin_addr"); addr != "" {
+			adminServer = &http.Server{Addr: addr, Handler: classifier.Admin()}
+
+			go func() {
+				log.Logger.WithField("addr", addr).Info("serving administration")
+				if err := adminServer.ListenAndServe(); err != http.ErrServerClosed {
+					log.FailOnError(err, "admin server failed")
+				}
+			}()
+		}
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+		// closed once in-flight requests have drained so the batcher is not closed beneath them
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			sig := <-signals
+			log.Logger.WithField("signal", sig.String()).Info("shutting down server")
+			cancel()
+
+			if grpcServer != nil {
+				grpcServer.GracefulStop()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if adminServer != nil {
+				adminServer.Shutdown(ctx)
+			}
+			if err := server.Shutdown(ctx); err != nil {
+				log.Logger.WithError(err).Error("failed to drain requests")
+			}
+		}()
+
+		log.Logger.WithField("addr", server.Addr).WithField("layers", classifier.Layers()).Info("serving neural network")
+
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			log.FailOnError(err, "server failed")
+		}
+
+		<-done
+
+		log.Logger.Info("server stopped")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("parameters", "parameters.csv", "trained parameters path or registry model reference (name@stage or name@version)")
+	serveCmd.Flags().String("quantized", "", "int8 quantized parameters path served instead of parameters")
+	serveCmd.Flags().String("addr", ":8080", "HTTP listen address")
+	serveCmd.Flags().String("admin_addr", "127.0.0.1:8081", "HTTP listen address of unauthenticated administration endpoints such as /admin/reload, empty disables")
+	serveCmd.Flags().Bool("grpc", false, "serve gRPC classification service alongside HTTP")
+	serveCmd.Flags().String("grpc_addr", ":9090", "gRPC listen address")
+	serveCmd.Flags().Int("input_width", 0, "features per sample parameters must accept (default is width of initially loaded parameters)")
+	serveCmd.Flags().Int("max_batch", 64, "single sample predictions coalesced into one forward pass, below 2 disables batching")
+	serveCmd.Flags().Duration("max_wait", 2*time.Millisecond, "time a batch waits to fill before it is classified")
+	serveCmd.Flags().Int64("max_body_bytes", 1<<20, "largest request body accepted in bytes")
+	serveCmd.Flags().Duration("watch_interval", 5*time.Second, "interval parameters file is checked for changes to reload, 0 disables")
+}
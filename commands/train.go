@@ -1,9 +1,21 @@
 package commands
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
+	"github.com/gregl83/go-binary-classify-nn/adapters"
 	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/metrics"
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/plot"
+	"github.com/gregl83/go-binary-classify-nn/lib"
 )
 
 // trainCmd represents the train command
@@ -11,19 +23,193 @@ var trainCmd = &cobra.Command{
 	Use:   "train",
 	Short: "Train neural network",
 	Long: "Train the neural network using a data source",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlags(cmd.Flags())
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Logger.Info("training neural network")
 
-		// todo - load data
+		store := adapters.NewStore(viper.GetString("parameters"))
+
+		data, labels, err := store.ReadData(viper.GetString("data"))
+		log.FailOnError(err, "failed to load data")
+
+		features, _ := data.Dims()
+		layers := append([]int{features}, intSlice(viper.GetStringSlice("layers"))...)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+
+		go func() {
+			select {
+			case sig := <-signals:
+				log.Logger.WithField("signal", sig.String()).Warn("stopping training after current step")
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		history := lib.NewHistory()
+		callbacks := []lib.Callback{}
+
+		evaluationData, evaluationLabels := data, labels
+		if path := viper.GetString("validation"); path != "" {
+			evaluationData, evaluationLabels, err = store.ReadData(path)
+			log.FailOnError(err, "failed to load validation data")
+			callbacks = append(callbacks, lib.NewValidation(evaluationData, evaluationLabels))
+		}
+
+		callbacks = append(callbacks, history, log.NewTrainingLogger(viper.GetInt("log_every")))
+
+		if file, push := viper.GetString("metrics_file"), viper.GetString("metrics_push"); file != "" || push != "" {
+			callbacks = append(callbacks, metrics.NewTrainingExporter(file, push, viper.GetInt("log_every")))
+		}
+
+		if viper.GetBool("batch_norm") && (viper.GetBool("float32") || viper.GetInt("workers") != 1) {
+			log.Logger.Warn("batch normalization trains in double precision with a single worker")
+		}
+
+		var parameters lib.Parameters
+		var costs []float64
+		if viper.GetBool("float32") && !viper.GetBool("batch_norm") {
+			parameters, costs = lib.Model32(
+				ctx,
+				data,
+				labels,
+				layers,
+				viper.GetFloat64("learning_rate"),
+				viper.GetInt("iterations"),
+				callbacks...,
+			)
+		} else {
+			parameters, costs = lib.ModelOptions(
+				ctx,
+				data,
+				labels,
+				layers,
+				viper.GetFloat64("learning_rate"),
+				viper.GetInt("iterations"),
+				lib.Options{Workers: viper.GetInt("workers"), BatchNorm: viper.GetBool("batch_norm")},
+				callbacks...,
+			)
+		}
+
+		header, err := store.ReadHeader(viper.GetString("data"))
+		log.FailOnError(err, "failed to load data header")
+
+		hash, err := store.Checksum(viper.GetString("data"))
+		log.FailOnError(err, "failed to hash data")
 
-		// todo - run train
+		// metadata is written first so saved parameters always have a model card to check inputs against
+		log.FailOnError(store.CreateMetadata(trainingMetadata(layers, header, hash, *history)), "failed to save metadata")
+		log.FailOnError(store.CreateParameters(parameters), "failed to save parameters")
 
-		// todo - persist trained weights
+		predictions := lib.Predict(parameters, evaluationData)
+		evaluation := lib.Evaluate(&predictions, &evaluationLabels, lib.Threshold)
 
-		log.Logger.Info("training completed")
+		if path := viper.GetString("history"); path != "" {
+			log.FailOnError(store.CreateHistory(path, viper.AllSettings(), *history), "failed to save history")
+			log.Logger.WithField("path", path).Info("history saved")
+		}
+
+		if path := viper.GetString("evaluation"); path != "" {
+			log.FailOnError(store.CreateEvaluation(path, evaluation), "failed to save evaluation")
+			log.Logger.WithField("path", path).Info("evaluation saved")
+		}
+
+		if dir := viper.GetString("plot_dir"); dir != "" {
+			log.FailOnError(savePlots(dir, viper.GetString("plot_format"), *history, evaluation), "failed to save plots")
+			log.Logger.WithField("path", dir).Info("plots saved")
+		}
+
+		if ctx.Err() != nil {
+			log.Logger.WithField("iterations", len(costs)).WithField("path", store.Path()).Info("training interrupted, checkpoint saved")
+			return
+		}
+
+		log.Logger.WithField("path", store.Path()).Info("training completed")
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(trainCmd)
-}
\ No newline at end of file
+
+	trainCmd.Flags().String("data", "", "training data CSV with label as last column, or .npz with X and Y arrays")
+	trainCmd.Flags().String("parameters", "parameters.csv", "path trained parameters are written to")
+	trainCmd.Flags().StringSlice("layers", []string{"1"}, "neurons per hidden and output layer")
+	trainCmd.Flags().Float64("learning_rate", 0.0075, "gradient descent learning rate")
+	trainCmd.Flags().Int("iterations", 3000, "gradient descent iterations")
+	trainCmd.Flags().String("validation", "", "validation data CSV with label as last column, or .npz with X and Y arrays")
+	trainCmd.Flags().Int("log_every", 100, "log training progress every n epochs")
+	trainCmd.Flags().String("plot_dir", "", "directory cost, metric, ROC and PR curve plots are written to")
+	trainCmd.Flags().String("plot_format", "svg", "plot image format (svg or png)")
+	trainCmd.Flags().String("history", "", "path training configuration and history JSON is written to")
+	trainCmd.Flags().String("metrics_file", "", "path Prometheus text format training metrics are written to every log_every epochs")
+	trainCmd.Flags().String("metrics_push", "", "Pushgateway URL training metrics are pushed to every log_every epochs")
+	trainCmd.Flags().String("evaluation", "", "path evaluation of validation (or training) data JSON is written to")
+	trainCmd.Flags().Bool("float32", false, "train computing in single precision")
+	trainCmd.Flags().Int("workers", 1, "goroutines each batch is split across, 0 for one per CPU (ignored with float32)")
+	trainCmd.Flags().Bool("batch_norm", false, "batch normalize pre-activations of hidden layers (ignores float32 and workers)")
+	trainCmd.Flags().String("notes", "", "free-form notes recorded in parameters metadata")
+}
+
+// trainingMetadata describes provenance of parameters trained with layers on data with header and hash
+func trainingMetadata(layers []int, header []string, hash string, history lib.History) adapters.Metadata {
+	var features []string
+	if len(header) > 0 {
+		features = header[:len(header)-1] // label
+	}
+
+	// features are not scaled by training so no scaler is recorded
+	threshold := lib.Threshold
+	metrics := lib.Metrics{}
+	for name, values := range history.Metrics {
+		if len(values) > 0 {
+			metrics[name] = values[len(values)-1]
+		}
+	}
+
+	return adapters.Metadata{
+		DatasetHash: hash,
+		Features:    features,
+		Hyperparameters: map[string]interface{}{
+			"layers":        layers,
+			"learning_rate": viper.GetFloat64("learning_rate"),
+			"iterations":    viper.GetInt("iterations"),
+			"float32":       viper.GetBool("float32"),
+			"workers":       viper.GetInt("workers"),
+			"batch_norm":    viper.GetBool("batch_norm"),
+		},
+		Metrics:   metrics,
+		Threshold: &threshold,
+		CreatedAt: time.Now().UTC(),
+		Version:   Version,
+		Notes:     viper.GetString("notes"),
+	}
+}
+
+// savePlots of training history and evaluation curves to dir
+func savePlots(dir, format string, history lib.History, evaluation lib.Evaluation) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	charts := map[string]plot.Chart{
+		"cost":     plot.HistoryChart(history, "cost", "cost", "val_cost"),
+		"accuracy": plot.HistoryChart(history, "accuracy", "accuracy", "val_accuracy"),
+		"roc":      plot.ROCChart(evaluation),
+		"pr":       plot.PRChart(evaluation),
+	}
+
+	for name, chart := range charts {
+		if err := chart.Save(filepath.Join(dir, name+"."+format)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+// Package bench measures prediction throughput and latency of trained parameters on the local machine
+package bench
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Predict computes output layer activations for data with a column per sample
+type Predict func(data mat.Dense) mat.Dense
+
+// Latency of predicting a batch
+type Latency struct {
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// Result of a benchmark of a batch size
+type Result struct {
+	BatchSize int `json:"batch_size"`
+	// Batches predicted excluding warmup
+	Batches int `json:"batches"`
+	// Samples predicted excluding warmup
+	Samples int `json:"samples"`
+	// Elapsed time predicting batches
+	Elapsed time.Duration `json:"elapsed"`
+	// SamplesPerSecond predicted
+	SamplesPerSecond float64 `json:"samples_per_second"`
+	Latency          Latency `json:"latency"`
+}
+
+// Run predicts consecutive batches of batchSize samples from data, cycling through data, for at least
+// duration after warmup batches and returns throughput and latency per batch
+func Run(predict Predict, data mat.Dense, batchSize int, duration time.Duration, warmup int) (Result, error) {
+	features, samples := data.Dims()
+	if batchSize < 1 || batchSize > samples {
+		return Result{}, fmt.Errorf("batch size %d must be between 1 and %d samples", batchSize, samples)
+	}
+
+	// batches are sliced before timing so only predictions are measured
+	batches := make([]mat.Dense, samples/batchSize)
+	for k := range batches {
+		batches[k] = *mat.DenseCopyOf(data.Slice(0, features, k*batchSize, (k+1)*batchSize))
+	}
+
+	for k := 0; k < warmup; k++ {
+		predict(batches[k%len(batches)])
+	}
+
+	var latencies []time.Duration
+	var elapsed time.Duration
+	for k := 0; elapsed < duration || len(latencies) == 0; k++ {
+		start := time.Now()
+		predict(batches[k%len(batches)])
+		latency := time.Since(start)
+
+		latencies = append(latencies, latency)
+		elapsed += latency
+	}
+
+	return Result{
+		BatchSize:        batchSize,
+		Batches:          len(latencies),
+		Samples:          len(latencies) * batchSize,
+		Elapsed:          elapsed,
+		SamplesPerSecond: float64(len(latencies)*batchSize) / elapsed.Seconds(),
+		Latency:          latency(latencies, elapsed),
+	}, nil
+}
+
+// latency summary of latencies totalling elapsed
+func latency(latencies []time.Duration, elapsed time.Duration) Latency {
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return Latency{
+		Mean: elapsed / time.Duration(len(sorted)),
+		P50:  percentile(sorted, 0.5),
+		P90:  percentile(sorted, 0.9),
+		P99:  percentile(sorted, 0.99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// percentile p (0-1) of sorted latencies by nearest rank
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
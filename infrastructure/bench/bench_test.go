@@ -0,0 +1,59 @@
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRun(t *testing.T) {
+	data := mat.NewDense(2, 5, []float64{
+		1, 2, 3, 4, 5,
+		6, 7, 8, 9, 10,
+	})
+
+	var predicted []float64
+	predict := func(batch mat.Dense) mat.Dense {
+		_, samples := batch.Dims()
+		assert.Equal(t, 2, samples)
+		predicted = append(predicted, batch.At(0, 0))
+		return *mat.NewDense(1, samples, nil)
+	}
+
+	result, err := Run(predict, *data, 2, 0, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 3, 1, 1}, predicted) // warmup cycles batches, trailing sample is unused
+	assert.Equal(t, 2, result.BatchSize)
+	assert.Equal(t, 1, result.Batches)
+	assert.Equal(t, 2, result.Samples)
+	assert.True(t, result.Latency.P50 <= result.Latency.Max)
+}
+
+func TestRunBatchSize(t *testing.T) {
+	data := mat.NewDense(1, 2, []float64{1, 2})
+	predict := func(batch mat.Dense) mat.Dense { return batch }
+
+	_, err := Run(predict, *data, 3, 0, 0)
+	assert.EqualError(t, err, "batch size 3 must be between 1 and 2 samples")
+
+	_, err = Run(predict, *data, 0, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 0.5))
+	assert.Equal(t, 90*time.Millisecond, percentile(sorted, 0.9))
+	assert.Equal(t, 99*time.Millisecond, percentile(sorted, 0.99))
+	assert.Equal(t, time.Millisecond, percentile(sorted, 0))
+
+	summary := latency(sorted[:4], 10*time.Millisecond)
+	assert.Equal(t, Latency{Mean: 2500 * time.Microsecond, P50: 2 * time.Millisecond, P90: 4 * time.Millisecond, P99: 4 * time.Millisecond, Max: 4 * time.Millisecond}, summary)
+}
@@ -0,0 +1,239 @@
+// Package codegen generates standalone Go source computing predictions of trained parameters
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// Source of a trained model depending only on the standard library
+type Source struct {
+	// Package name of generated source
+	Package string
+	// Parameters trained
+	Parameters lib.Parameters
+	// Samples the generated test asserts Predict matches PropagateForward on
+	Samples [][]float64
+}
+
+// layer of generated source
+type layer struct {
+	Index      int
+	Inputs     int
+	Units      int
+	Activation string
+	Weights    string
+	Bias       string
+	// Sums unrolled per unit
+	Sums []string
+	// Output variable of layer
+	Output string
+}
+
+// example of generated test
+type example struct {
+	Features string
+	Expected string
+}
+
+// Go writes source with weights as arrays and an unrolled Predict function, batch normalization folded into them
+func (s Source) Go(w io.Writer) error {
+	if err := s.Parameters.Validate(0); err != nil {
+		return err
+	}
+	s.Parameters = s.Parameters.Fold()
+
+	return render(w, model, map[string]interface{}{
+		"Package":   s.Package,
+		"Features":  s.Parameters.Layers[0],
+		"Threshold": formatFloat(lib.Threshold),
+		"Layers":    s.layers(),
+		"Output":    variable(len(s.Parameters.Layers) - 1),
+	})
+}
+
+// Test writes a test asserting Predict matches PropagateForward for each sample
+func (s Source) Test(w io.Writer) error {
+	if err := s.Parameters.Validate(0); err != nil {
+		return err
+	}
+
+	examples := make([]example, len(s.Samples))
+	for i, sample := range s.Samples {
+		if len(sample) != s.Parameters.Layers[0] {
+			return fmt.Errorf("sample %d has %d features, expected %d", i, len(sample), s.Parameters.Layers[0])
+		}
+
+		predictions := lib.Predict(s.Parameters, *mat.NewDense(len(sample), 1, sample))
+		examples[i] = example{Features: formatFloats(sample), Expected: formatFloat(predictions.At(0, 0))}
+	}
+
+	return render(w, test, map[string]interface{}{
+		"Package":  s.Package,
+		"Examples": examples,
+	})
+}
+
+func (s Source) layers() []layer {
+	layers := make([]layer, 0, len(s.Parameters.Layers)-1)
+
+	for l := 1; l < len(s.Parameters.Layers); l++ {
+		weights, bias := &s.Parameters.Weights[l], &s.Parameters.Bias[l]
+		units, inputs := weights.Dims()
+
+		rows := make([]string, units)
+		sums := make([]string, units)
+		for i := 0; i < units; i++ {
+			rows[i] = "{" + formatFloats(weights.RawRowView(i)) + "}"
+
+			terms := []string{fmt.Sprintf("bias%d[%d]", l, i)}
+			for j := 0; j < inputs; j++ {
+				terms = append(terms, fmt.Sprintf("weights%d[%d][%d]*%s[%d]", l, i, j, variable(l-1), j))
+			}
+			sums[i] = strings.Join(terms, " + ")
+		}
+
+		layers = append(layers, layer{
+			Index:      l,
+			Inputs:     inputs,
+			Units:      units,
+			Activation: s.Parameters.Activation(l),
+			Weights:    strings.Join(rows, ",\n"),
+			Bias:       formatFloats(mat.Col(nil, 0, bias)),
+			Sums:       sums,
+			Output:     variable(l),
+		})
+	}
+
+	return layers
+}
+
+// variable holding activations of layer
+func variable(layer int) string {
+	if layer == 0 {
+		return "features"
+	}
+	return "a" + strconv.Itoa(layer)
+}
+
+func render(w io.Writer, t *template.Template, data interface{}) error {
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return err
+	}
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(source)
+	return err
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatFloats(values []float64) string {
+	fields := make([]string, len(values))
+	for i, value := range values {
+		fields[i] = formatFloat(value)
+	}
+	return strings.Join(fields, ", ")
+}
+
+var model = template.Must(template.New("model").Parse(`// Code generated by go-binary-classify-nn codegen. DO NOT EDIT.
+
+// Package {{.Package}} predicts with a trained binary classification neural network
+package {{.Package}}
+
+import "math"
+
+// Features expected by Predict
+const Features = {{.Features}}
+
+// Threshold of score at or above which a sample is classified positive
+const Threshold = {{.Threshold}}
+{{range .Layers}}
+// weights{{.Index}} of layer {{.Index}}, a row of {{.Inputs}} weights per unit
+var weights{{.Index}} = [{{.Units}}][{{.Inputs}}]float64{
+{{.Weights}},
+}
+
+// bias{{.Index}} of layer {{.Index}} units
+var bias{{.Index}} = [{{.Units}}]float64{ {{.Bias}} }
+{{end}}
+// Predict score of sample features, the probability the sample is positive
+//
+// Predict panics when features has fewer than Features values.
+func Predict(features []float64) float64 {
+	_ = features[Features-1]
+{{range .Layers}}
+	var {{.Output}} [{{.Units}}]float64
+{{- $layer := .}}
+{{- range $i, $sum := .Sums}}
+	{{$layer.Output}}[{{$i}}] = {{$layer.Activation}}({{$sum}})
+{{- end}}
+{{end}}
+	return {{.Output}}[0]
+}
+
+// Classify sample features as positive (1) or negative (0)
+func Classify(features []float64) int {
+	if Predict(features) >= Threshold {
+		return 1
+	}
+	return 0
+}
+
+func relu(z float64) float64 {
+	if z > 0 {
+		return z
+	}
+	return 0
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+`))
+
+var test = template.Must(template.New("test").Parse(`// Code generated by go-binary-classify-nn codegen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"math"
+	"testing"
+)
+
+// tolerance of summation order differences from the trained network's matrix products
+const tolerance = 1e-9
+
+func TestPredict(t *testing.T) {
+	examples := []struct {
+		features []float64
+		expected float64
+	}{
+{{- range .Examples}}
+		{[]float64{ {{.Features}} }, {{.Expected}}},
+{{- end}}
+	}
+
+	for i, example := range examples {
+		if actual := Predict(example.features); math.Abs(actual-example.expected) > tolerance {
+			t.Errorf("example %d: Predict = %v, expected %v", i, actual, example.expected)
+		}
+	}
+}
+`))
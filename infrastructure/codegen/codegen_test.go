@@ -0,0 +1,55 @@
+package codegen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+func testSource() Source {
+	parameters := lib.NewParameters([]int{2, 2, 1})
+	parameters.Weights[1] = *mat.NewDense(2, 2, []float64{1, -1, 0.5, 2})
+	parameters.Bias[1] = *mat.NewDense(2, 1, []float64{0, -1})
+	parameters.Weights[2] = *mat.NewDense(1, 2, []float64{1.5, -0.25})
+	parameters.Bias[2] = *mat.NewDense(1, 1, []float64{0.1})
+
+	return Source{Package: "churn", Parameters: parameters, Samples: [][]float64{{1, 2}, {3, -1}}}
+}
+
+func TestSourceGo(t *testing.T) {
+	var b bytes.Buffer
+
+	assert.NoError(t, testSource().Go(&b))
+
+	source := b.String()
+	assert.Contains(t, source, "package churn")
+	assert.Contains(t, source, "const Features = 2")
+	assert.Contains(t, source, "var weights1 = [2][2]float64{\n\t{1, -1},\n\t{0.5, 2},\n}")
+	assert.Contains(t, source, "var bias2 = [1]float64{0.1}")
+	assert.Contains(t, source, "a1[1] = relu(bias1[1] + weights1[1][0]*features[0] + weights1[1][1]*features[1])")
+	assert.Contains(t, source, "a2[0] = sigmoid(bias2[0] + weights2[0][0]*a1[0] + weights2[0][1]*a1[1])")
+	assert.Contains(t, source, "return a2[0]")
+	assert.NotContains(t, source, "gonum")
+}
+
+func TestSourceTest(t *testing.T) {
+	var b bytes.Buffer
+	source := testSource()
+
+	assert.NoError(t, source.Test(&b))
+
+	expected := lib.Predict(source.Parameters, *mat.NewDense(2, 1, []float64{1, 2}))
+	assert.Contains(t, b.String(), "{[]float64{1, 2}, "+formatFloat(expected.At(0, 0))+"},")
+	assert.Contains(t, b.String(), "func TestPredict(t *testing.T) {")
+}
+
+func TestSourceTestFeatureMismatch(t *testing.T) {
+	source := testSource()
+	source.Samples = [][]float64{{1}}
+
+	assert.EqualError(t, source.Test(&bytes.Buffer{}), "sample 0 has 1 features, expected 2")
+}
@@ -2,14 +2,35 @@
 package log
 
 import (
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/sirupsen/logrus"
 )
 
 var (
 	// Logger is a logrus instance with line formatter
 	Logger *logrus.Logger
+
+	// output of Logger when writing to a file
+	output io.Closer
 )
 
+// Options configuring Logger
+type Options struct {
+	// Level of logs emitted (panic, fatal, error, warn, info, debug)
+	Level string
+	// Format of logs (text or json)
+	Format string
+	// File logs are written to instead of stderr
+	File string
+	// MaxSize of File in bytes before it is rotated, zero disables rotation
+	MaxSize int64
+	// MaxBackups of rotated files kept
+	MaxBackups int
+}
+
 func init() {
 	Logger = logrus.New()
 
@@ -20,6 +41,48 @@ func init() {
 	Logger.SetLevel(logrus.DebugLevel)
 }
 
+// Configure Logger level, formatter and output
+func Configure(options Options) error {
+	if options.Level != "" {
+		level, err := logrus.ParseLevel(options.Level)
+		if err != nil {
+			return err
+		}
+		Logger.SetLevel(level)
+	}
+
+	switch options.Format {
+	case "", "text":
+		Logger.Formatter = &LineFormatter{
+			FullTimestamp: true,
+		}
+	case "json":
+		Logger.Formatter = &logrus.JSONFormatter{}
+	default:
+		return fmt.Errorf("unknown log format %q", options.Format)
+	}
+
+	if output != nil {
+		output.Close()
+		output = nil
+	}
+
+	if options.File == "" {
+		Logger.Out = os.Stderr
+		return nil
+	}
+
+	file := &RotatingFile{
+		Path:       options.File,
+		MaxSize:    options.MaxSize,
+		MaxBackups: options.MaxBackups,
+	}
+	Logger.Out = file
+	output = file
+
+	return nil
+}
+
 // FailOnError logs a fatal when err isn't nil (dies)
 func FailOnError(err error, message string) {
 	if err != nil {
@@ -32,4 +95,4 @@ func FailOnEmptyString(val string, message string) {
 	if val == "" {
 		Logger.Fatalf("%s", message)
 	}
-}
\ No newline at end of file
+}
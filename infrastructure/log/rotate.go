@@ -0,0 +1,105 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFile writer rotating the file once it exceeds a maximum size
+//
+// Rotated files are renamed with a numeric suffix (path.1 being the most recent) and at most MaxBackups
+// are kept.
+type RotatingFile struct {
+	// Path of the active log file
+	Path string
+	// MaxSize in bytes before rotating, zero disables rotation
+	MaxSize int64
+	// MaxBackups of rotated files kept
+	MaxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Write p to the active file rotating beforehand when p would exceed MaxSize
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.MaxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+// Close the active file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	if r.MaxBackups < 1 {
+		os.Remove(r.Path)
+	} else {
+		os.Remove(r.backup(r.MaxBackups))
+		for i := r.MaxBackups - 1; i > 0; i-- {
+			os.Rename(r.backup(i), r.backup(i+1))
+		}
+		if err := os.Rename(r.Path, r.backup(1)); err != nil {
+			return err
+		}
+	}
+
+	return r.open()
+}
+
+func (r *RotatingFile) backup(i int) string {
+	return fmt.Sprintf("%s.%d", r.Path, i)
+}
@@ -0,0 +1,35 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "worker.log")
+	file := &RotatingFile{Path: path, MaxSize: 10, MaxBackups: 2}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, file.Close())
+
+	contents := map[string]string{}
+	for _, name := range []string{path, path + ".1", path + ".2", path + ".3"} {
+		b, _ := os.ReadFile(name)
+		contents[name] = string(b)
+	}
+
+	assert.Equal(t, "fourth\n", contents[path])
+	assert.Equal(t, "third\n", contents[path+".1"])
+	assert.Equal(t, "second\n", contents[path+".2"])
+	assert.Equal(t, "", contents[path+".3"])
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// TrainingLogger callback logs structured training progress every n epochs
+type TrainingLogger struct {
+	lib.BaseCallback
+	every int
+	start time.Time
+}
+
+// OnTrainBegin logs training start
+func (t *TrainingLogger) OnTrainBegin(progress *lib.Progress) {
+	t.start = time.Now()
+
+	Logger.WithFields(logrus.Fields{
+		"layers":        progress.Parameters.Layers,
+		"learning_rate": progress.LearningRate,
+	}).Info("training started")
+}
+
+// OnEpochEnd logs epoch progress and metrics
+func (t *TrainingLogger) OnEpochEnd(progress *lib.Progress) {
+	if progress.Epoch%t.every != 0 {
+		return
+	}
+
+	Logger.WithFields(t.fields(progress)).Info("epoch completed")
+}
+
+// OnTrainEnd logs final progress and metrics
+func (t *TrainingLogger) OnTrainEnd(progress *lib.Progress) {
+	Logger.WithFields(t.fields(progress)).Info("training ended")
+}
+
+func (t *TrainingLogger) fields(progress *lib.Progress) logrus.Fields {
+	fields := logrus.Fields{
+		"epoch":         progress.Epoch,
+		"step":          progress.Step,
+		"cost":          progress.Cost,
+		"learning_rate": progress.LearningRate,
+		"gradient_norm": progress.GradientNorm,
+		"elapsed":       time.Since(t.start).String(),
+	}
+
+	for name, value := range progress.Metrics {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+
+	return fields
+}
+
+// NewTrainingLogger callback logging every n epochs
+func NewTrainingLogger(every int) *TrainingLogger {
+	if every < 1 {
+		every = 1
+	}
+	return &TrainingLogger{every: every}
+}
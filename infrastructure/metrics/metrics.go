@@ -0,0 +1,238 @@
+// Package metrics implements counters, gauges and histograms exposed in the Prometheus text format
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// contentType of the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metric family of series sharing a name and label names
+type metric struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu      sync.Mutex
+	series  map[string]*series
+	buckets []float64
+}
+
+// series of a metric for a combination of label values
+type series struct {
+	labels []string
+	value  float64
+	counts []uint64
+	count  uint64
+}
+
+func (m *metric) get(labels []string) *series {
+	if len(labels) != len(m.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", m.name, len(m.labels), len(labels)))
+	}
+
+	key := strings.Join(labels, "\xff")
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labels: labels, counts: make([]uint64, len(m.buckets))}
+		m.series[key] = s
+	}
+
+	return s
+}
+
+func (m *metric) write(b *bytes.Buffer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintf(b, "# HELP %s %s\n", m.name, strings.NewReplacer("\\", `\\`, "\n", `\n`).Replace(m.help))
+	fmt.Fprintf(b, "# TYPE %s %s\n", m.name, m.kind)
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := m.series[key]
+
+		if m.kind != "histogram" {
+			fmt.Fprintf(b, "%s%s %s\n", m.name, m.format(s.labels, "", 0), formatValue(s.value))
+			continue
+		}
+
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(b, "%s_bucket%s %d\n", m.name, m.format(s.labels, "le", bound), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", m.name, m.format(s.labels, "le", math.Inf(1)), s.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", m.name, m.format(s.labels, "", 0), formatValue(s.value))
+		fmt.Fprintf(b, "%s_count%s %d\n", m.name, m.format(s.labels, "", 0), s.count)
+	}
+}
+
+// format label pairs including an extra label when name isn't empty
+func (m *metric) format(values []string, name string, value float64) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, label := range m.labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, label, escape(values[i])))
+	}
+	if name != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, formatValue(value)))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escape(value string) string {
+	return strings.NewReplacer("\\", `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Counter of monotonically increasing values
+type Counter struct {
+	metric *metric
+}
+
+// Inc counter of label values by one
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+// Add value to counter of label values
+func (c *Counter) Add(value float64, labels ...string) {
+	c.metric.mu.Lock()
+	c.metric.get(labels).value += value
+	c.metric.mu.Unlock()
+}
+
+// Gauge of values that go up and down
+type Gauge struct {
+	metric *metric
+}
+
+// Set gauge of label values
+func (g *Gauge) Set(value float64, labels ...string) {
+	g.metric.mu.Lock()
+	g.metric.get(labels).value = value
+	g.metric.mu.Unlock()
+}
+
+// Histogram of observations counted in buckets
+type Histogram struct {
+	metric *metric
+}
+
+// Observe value for label values
+func (h *Histogram) Observe(value float64, labels ...string) {
+	h.metric.mu.Lock()
+	defer h.metric.mu.Unlock()
+
+	s := h.metric.get(labels)
+	for i, bound := range h.metric.buckets {
+		if value <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+// Registry of metrics written together
+type Registry struct {
+	mu      sync.Mutex
+	metrics []*metric
+}
+
+// Counter registered with name, help and label names
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{metric: r.register(name, help, "counter", labels, nil)}
+}
+
+// Gauge registered with name, help and label names
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{metric: r.register(name, help, "gauge", labels, nil)}
+}
+
+// Histogram registered with name, help, ascending bucket upper bounds and label names
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{metric: r.register(name, help, "histogram", labels, buckets)}
+}
+
+func (r *Registry) register(name, help, kind string, labels []string, buckets []float64) *metric {
+	m := &metric{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: map[string]*series{}}
+
+	r.mu.Lock()
+	r.metrics = append(r.metrics, m)
+	r.mu.Unlock()
+
+	return m
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+
+	r.mu.Lock()
+	for _, m := range r.metrics {
+		m.write(&b)
+	}
+	r.mu.Unlock()
+
+	return b.WriteTo(w)
+}
+
+// ServeHTTP exposes metrics for scraping
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	r.WriteTo(w)
+}
+
+// NewRegistry of metrics
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// LinearBuckets of count upper bounds starting at start spaced by width
+func LinearBuckets(start, width float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start + width*float64(i)
+	}
+	return buckets
+}
+
+// ExponentialBuckets of count upper bounds starting at start multiplied by factor
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start * math.Pow(factor, float64(i))
+	}
+	return buckets
+}
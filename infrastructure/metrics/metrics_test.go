@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	requests := registry.Counter("requests_total", "Requests served.", "path", "code")
+	requests.Inc("/v1/predict", "200")
+	requests.Inc("/v1/predict", "200")
+	requests.Inc("/v1/predict", "400")
+
+	version := registry.Gauge("model_version", "Version of served model.")
+	version.Set(3)
+
+	latency := registry.Histogram("latency_seconds", "Request latency.", []float64{0.1, 1})
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(2)
+
+	var b bytes.Buffer
+	_, err := registry.WriteTo(&b)
+	assert.NoError(t, err)
+
+	assert.Equal(t, `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{path="/v1/predict",code="200"} 2
+requests_total{path="/v1/predict",code="400"} 1
+# HELP model_version Version of served model.
+# TYPE model_version gauge
+model_version 3
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 2.55
+latency_seconds_count 3
+`, b.String())
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	registry.Gauge("up", "Up.", "name").Set(1, `a"b`)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, contentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `up{name="a\"b"} 1`)
+}
+
+func TestBuckets(t *testing.T) {
+	assert.Equal(t, []float64{0.1, 0.2, 0.30000000000000004}, LinearBuckets(0.1, 0.1, 3))
+	assert.Equal(t, []float64{0.001, 0.01, 0.1}, ExponentialBuckets(0.001, 10, 3))
+}
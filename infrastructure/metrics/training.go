@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/log"
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// TrainingExporter callback exporting training progress every n epochs
+//
+// Metrics are written to a file for the node exporter textfile collector and/or pushed to a Pushgateway
+// URL (for example http://pushgateway:9091/metrics/job/train), neither requiring a live Prometheus server.
+type TrainingExporter struct {
+	lib.BaseCallback
+
+	file   string
+	push   string
+	every  int
+	client *http.Client
+
+	registry     *Registry
+	epoch        *Gauge
+	step         *Gauge
+	cost         *Gauge
+	learningRate *Gauge
+	gradientNorm *Gauge
+	metric       *Gauge
+}
+
+// OnEpochEnd updates gauges exporting every n epochs
+func (e *TrainingExporter) OnEpochEnd(progress *lib.Progress) {
+	e.epoch.Set(float64(progress.Epoch))
+	e.step.Set(float64(progress.Step))
+	e.cost.Set(progress.Cost)
+	e.learningRate.Set(progress.LearningRate)
+	e.gradientNorm.Set(progress.GradientNorm)
+	for name, value := range progress.Metrics {
+		e.metric.Set(value, name)
+	}
+
+	if progress.Epoch%e.every == 0 {
+		e.export()
+	}
+}
+
+// OnTrainEnd exports final metrics
+func (e *TrainingExporter) OnTrainEnd(progress *lib.Progress) {
+	e.export()
+}
+
+func (e *TrainingExporter) export() {
+	var b bytes.Buffer
+	e.registry.WriteTo(&b)
+
+	if e.file != "" {
+		if err := writeFile(e.file, b.Bytes()); err != nil {
+			log.Logger.WithError(err).WithField("path", e.file).Warn("failed to write training metrics")
+		}
+	}
+
+	if e.push != "" {
+		if err := e.pushMetrics(b.Bytes()); err != nil {
+			log.Logger.WithError(err).WithField("url", e.push).Warn("failed to push training metrics")
+		}
+	}
+}
+
+func (e *TrainingExporter) pushMetrics(body []byte) error {
+	request, err := http.NewRequest(http.MethodPut, e.push, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	response, err := e.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("pushgateway responded %s", response.Status)
+	}
+
+	return nil
+}
+
+// writeFile atomically so collectors never read a partial file
+func writeFile(path string, contents []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// NewTrainingExporter callback writing metrics to file and/or pushing them to a Pushgateway URL every n epochs
+func NewTrainingExporter(file, push string, every int) *TrainingExporter {
+	if every < 1 {
+		every = 1
+	}
+
+	registry := NewRegistry()
+
+	return &TrainingExporter{
+		file:         file,
+		push:         push,
+		every:        every,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		registry:     registry,
+		epoch:        registry.Gauge("training_epoch", "Latest completed training epoch."),
+		step:         registry.Gauge("training_step", "Gradient updates completed."),
+		cost:         registry.Gauge("training_cost", "Cost of latest training step."),
+		learningRate: registry.Gauge("training_learning_rate", "Learning rate applied to gradients."),
+		gradientNorm: registry.Gauge("training_gradient_norm", "L2 norm of gradients of latest training step."),
+		metric:       registry.Gauge("training_metric", "Metrics of latest training epoch by name.", "name"),
+	}
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+func TestTrainingExporter(t *testing.T) {
+	dir, err := os.MkdirTemp("", "metrics")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var pushed []string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/metrics/job/train", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		pushed = append(pushed, string(body))
+	}))
+	defer gateway.Close()
+
+	path := filepath.Join(dir, "train.prom")
+	exporter := NewTrainingExporter(path, gateway.URL+"/metrics/job/train", 2)
+
+	for epoch := 0; epoch < 3; epoch++ {
+		exporter.OnEpochEnd(&lib.Progress{
+			Epoch:        epoch,
+			Step:         epoch + 1,
+			Cost:         0.5,
+			LearningRate: 0.01,
+			GradientNorm: 0.25,
+			Metrics:      map[string]float64{"accuracy": 0.75},
+		})
+	}
+	exporter.OnTrainEnd(&lib.Progress{})
+
+	assert.Len(t, pushed, 3)
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, pushed[2], string(contents))
+	assert.Contains(t, string(contents), "training_epoch 2\n")
+	assert.Contains(t, string(contents), "training_step 3\n")
+	assert.Contains(t, string(contents), "training_cost 0.5\n")
+	assert.Contains(t, string(contents), "training_learning_rate 0.01\n")
+	assert.Contains(t, string(contents), "training_gradient_norm 0.25\n")
+	assert.Contains(t, string(contents), `training_metric{name="accuracy"} 0.75`)
+}
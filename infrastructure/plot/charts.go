@@ -0,0 +1,53 @@
+package plot
+
+import (
+	"fmt"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// HistoryChart of history metrics by epoch, metrics missing from history are skipped
+func HistoryChart(history lib.History, title string, metrics ...string) Chart {
+	chart := Chart{Title: title, XLabel: "epoch", YLabel: title}
+
+	epochs := make([]float64, len(history.Epochs))
+	for i, epoch := range history.Epochs {
+		epochs[i] = float64(epoch)
+	}
+
+	for _, name := range metrics {
+		if values, ok := history.Metrics[name]; ok {
+			chart.Series = append(chart.Series, Series{Name: name, X: epochs, Y: values})
+		}
+	}
+
+	return chart
+}
+
+// ROCChart of evaluation receiver operating characteristic curve
+func ROCChart(evaluation lib.Evaluation) Chart {
+	return Chart{
+		Title:  fmt.Sprintf("ROC (AUC %.4f)", evaluation.ROCAUC),
+		XLabel: "false positive rate",
+		YLabel: "true positive rate",
+		Series: []Series{curve("roc", evaluation.ROC), {Name: "chance", X: []float64{0, 1}, Y: []float64{0, 1}}},
+	}
+}
+
+// PRChart of evaluation precision recall curve
+func PRChart(evaluation lib.Evaluation) Chart {
+	return Chart{
+		Title:  fmt.Sprintf("Precision Recall (AP %.4f)", evaluation.AveragePrecision),
+		XLabel: "recall",
+		YLabel: "precision",
+		Series: []Series{curve("pr", evaluation.PR)},
+	}
+}
+
+func curve(name string, points []lib.Point) Series {
+	series := Series{Name: name, X: make([]float64, len(points)), Y: make([]float64, len(points))}
+	for i, point := range points {
+		series.X[i], series.Y[i] = point.X, point.Y
+	}
+	return series
+}
@@ -0,0 +1,169 @@
+// Package plot renders line charts as SVG or PNG without external tools
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	defaultWidth  = 640
+	defaultHeight = 400
+	ticks         = 5
+
+	// margins around plot area
+	marginTop    = 40
+	marginRight  = 20
+	marginBottom = 50
+	marginLeft   = 70
+)
+
+// palette of series colors
+var palette = []color.RGBA{
+	{R: 31, G: 119, B: 180, A: 255},
+	{R: 255, G: 127, B: 14, A: 255},
+	{R: 44, G: 160, B: 44, A: 255},
+	{R: 214, G: 39, B: 40, A: 255},
+	{R: 148, G: 103, B: 189, A: 255},
+}
+
+// Series of points drawn as a line
+type Series struct {
+	Name string
+	X    []float64
+	Y    []float64
+}
+
+// Chart of line series
+type Chart struct {
+	Title  string
+	XLabel string
+	YLabel string
+	Series []Series
+	// Width and Height in pixels (default 640 x 400)
+	Width  int
+	Height int
+}
+
+// Save chart to path as SVG or PNG depending on extension
+func (c Chart) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	switch filepath.Ext(path) {
+	case ".svg":
+		err = c.SVG(file)
+	case ".png":
+		err = c.PNG(file)
+	default:
+		err = fmt.Errorf("unsupported plot format %q", filepath.Ext(path))
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// frame maps series values to pixel coordinates
+type frame struct {
+	width, height          int
+	minX, maxX, minY, maxY float64
+}
+
+// frame of chart bounding finite points of its series, which must have as many Y as X values
+func (c Chart) frame() (frame, error) {
+	f := frame{
+		width:  c.Width,
+		height: c.Height,
+		minX:   math.Inf(1),
+		maxX:   math.Inf(-1),
+		minY:   math.Inf(1),
+		maxY:   math.Inf(-1),
+	}
+	if f.width == 0 {
+		f.width = defaultWidth
+	}
+	if f.height == 0 {
+		f.height = defaultHeight
+	}
+
+	for _, series := range c.Series {
+		if len(series.Y) != len(series.X) {
+			return frame{}, fmt.Errorf("series %q has %d x and %d y values", series.Name, len(series.X), len(series.Y))
+		}
+		for i := range series.X {
+			if !finite(series.X[i], series.Y[i]) {
+				continue
+			}
+			f.minX, f.maxX = math.Min(f.minX, series.X[i]), math.Max(f.maxX, series.X[i])
+			f.minY, f.maxY = math.Min(f.minY, series.Y[i]), math.Max(f.maxY, series.Y[i])
+		}
+	}
+
+	if math.IsInf(f.minX, 1) {
+		f.minX, f.maxX, f.minY, f.maxY = 0, 1, 0, 1
+	}
+	if f.minX == f.maxX {
+		f.minX, f.maxX = f.minX-0.5, f.maxX+0.5
+	}
+	if f.minY == f.maxY {
+		f.minY, f.maxY = f.minY-0.5, f.maxY+0.5
+	}
+
+	return f, nil
+}
+
+// finite reports whether point x, y can be drawn, non-finite points such as NaN costs of diverged
+// training are skipped
+func finite(x, y float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0) && !math.IsNaN(y) && !math.IsInf(y, 0)
+}
+
+func (f frame) x(v float64) float64 {
+	return marginLeft + (v-f.minX)/(f.maxX-f.minX)*float64(f.width-marginLeft-marginRight)
+}
+
+func (f frame) y(v float64) float64 {
+	return float64(f.height-marginBottom) - (v-f.minY)/(f.maxY-f.minY)*float64(f.height-marginTop-marginBottom)
+}
+
+func (f frame) left() float64 {
+	return marginLeft
+}
+
+func (f frame) right() float64 {
+	return float64(f.width - marginRight)
+}
+
+func (f frame) top() float64 {
+	return marginTop
+}
+
+func (f frame) bottom() float64 {
+	return float64(f.height - marginBottom)
+}
+
+// ticks evenly spaced between min and max inclusive
+func tickValues(min, max float64) []float64 {
+	values := make([]float64, ticks)
+	for i := range values {
+		values[i] = min + (max-min)*float64(i)/float64(ticks-1)
+	}
+	return values
+}
+
+func tickLabel(v float64) string {
+	return strconv.FormatFloat(v, 'g', 3, 64)
+}
+
+func seriesColor(i int) color.RGBA {
+	return palette[i%len(palette)]
+}
@@ -0,0 +1,64 @@
+package plot
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var chart = Chart{
+	Title:  "Cost",
+	XLabel: "epoch",
+	YLabel: "cost",
+	Series: []Series{
+		{Name: "train", X: []float64{0, 1, 2}, Y: []float64{0.7, 0.5, 0.4}},
+		{Name: "validation", X: []float64{0, 1, 2}, Y: []float64{0.7, 0.6, 0.55}},
+	},
+}
+
+func TestSVG(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, chart.SVG(&b))
+
+	svg := b.String()
+	assert.True(t, strings.HasPrefix(svg, "<svg"))
+	assert.Equal(t, 2, strings.Count(svg, "<polyline"))
+	assert.Contains(t, svg, "validation")
+	assert.Contains(t, svg, `points="70.00,40.00 345.00,246.67 620.00,350.00"`)
+}
+
+func TestPNG(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, chart.PNG(&b))
+
+	img, err := png.Decode(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultWidth, img.Bounds().Dx())
+	assert.Equal(t, defaultHeight, img.Bounds().Dy())
+}
+
+func TestNonFinite(t *testing.T) {
+	nan := math.NaN()
+	chart := Chart{Series: []Series{
+		{Name: "train", X: []float64{0, 1, 2, 3}, Y: []float64{0.7, nan, 0.4, math.Inf(1)}},
+	}}
+
+	var b bytes.Buffer
+	assert.NoError(t, chart.SVG(&b))
+	assert.Contains(t, b.String(), `points="70.00,40.00 620.00,350.00"`)
+
+	b.Reset()
+	assert.NoError(t, chart.PNG(&b))
+}
+
+func TestMismatchedSeries(t *testing.T) {
+	chart := Chart{Series: []Series{{Name: "train", X: []float64{0, 1}, Y: []float64{0.7}}}}
+
+	var b bytes.Buffer
+	assert.EqualError(t, chart.SVG(&b), `series "train" has 2 x and 1 y values`)
+	assert.EqualError(t, chart.PNG(&b), `series "train" has 2 x and 1 y values`)
+}
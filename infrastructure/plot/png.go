@@ -0,0 +1,94 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	black = color.RGBA{A: 255}
+	grid  = color.RGBA{R: 221, G: 221, B: 221, A: 255}
+)
+
+// PNG renders chart as a raster image
+func (c Chart) PNG(w io.Writer) error {
+	f, err := c.frame()
+	if err != nil {
+		return err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, f.width, f.height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	text(img, c.Title, float64(f.width)/2, marginTop/2, 0.5)
+
+	for _, v := range tickValues(f.minX, f.maxX) {
+		line(img, f.x(v), f.top(), f.x(v), f.bottom(), grid, 1)
+		text(img, tickLabel(v), f.x(v), f.bottom()+18, 0.5)
+	}
+	for _, v := range tickValues(f.minY, f.maxY) {
+		line(img, f.left(), f.y(v), f.right(), f.y(v), grid, 1)
+		text(img, tickLabel(v), f.left()-6, f.y(v)+4, 1)
+	}
+
+	line(img, f.left(), f.top(), f.right(), f.top(), black, 1)
+	line(img, f.left(), f.bottom(), f.right(), f.bottom(), black, 1)
+	line(img, f.left(), f.top(), f.left(), f.bottom(), black, 1)
+	line(img, f.right(), f.top(), f.right(), f.bottom(), black, 1)
+
+	text(img, c.XLabel, (f.left()+f.right())/2, float64(f.height-10), 0.5)
+	text(img, c.YLabel, f.left(), f.top()-6, 0.5)
+
+	for i, series := range c.Series {
+		previous := -1
+		for j := range series.X {
+			if !finite(series.X[j], series.Y[j]) {
+				continue
+			}
+			if previous >= 0 {
+				line(img, f.x(series.X[previous]), f.y(series.Y[previous]), f.x(series.X[j]), f.y(series.Y[j]), seriesColor(i), 2)
+			}
+			previous = j
+		}
+
+		legendY := f.top() + 16 + float64(i)*16
+		line(img, f.right()-110, legendY, f.right()-90, legendY, seriesColor(i), 2)
+		text(img, series.Name, f.right()-84, legendY+4, 0)
+	}
+
+	return png.Encode(w, img)
+}
+
+// line drawn between two points with a square pen of width pixels
+func line(img *image.RGBA, x0, y0, x1, y1 float64, c color.Color, width int) {
+	steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))) + 1
+	for s := 0; s <= steps; s++ {
+		t := float64(s) / float64(steps)
+		x := int(math.Round(x0 + (x1-x0)*t))
+		y := int(math.Round(y0 + (y1-y0)*t))
+		for dx := 0; dx < width; dx++ {
+			for dy := 0; dy < width; dy++ {
+				img.Set(x+dx, y+dy, c)
+			}
+		}
+	}
+}
+
+// text drawn with baseline at y and horizontally anchored at x (0 left, 0.5 middle, 1 right)
+func text(img *image.RGBA, s string, x, y, anchor float64) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(black),
+		Face: basicfont.Face7x13,
+	}
+	width := float64(drawer.MeasureString(s).Round())
+	drawer.Dot = fixed.P(int(x-width*anchor), int(y))
+	drawer.DrawString(s)
+}
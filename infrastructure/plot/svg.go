@@ -0,0 +1,64 @@
+package plot
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// SVG renders chart as scalable vector graphics
+func (c Chart) SVG(w io.Writer) error {
+	f, err := c.frame()
+	if err != nil {
+		return err
+	}
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", f.width, f.height, f.width, f.height)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="white"/>`+"\n", f.width, f.height)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" font-size="14">%s</text>`+"\n", f.width/2, marginTop/2, escape(c.Title))
+
+	for _, v := range tickValues(f.minX, f.maxX) {
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#ddd"/>`+"\n", f.x(v), f.top(), f.x(v), f.bottom())
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="middle">%s</text>`+"\n", f.x(v), f.bottom()+16, tickLabel(v))
+	}
+	for _, v := range tickValues(f.minY, f.maxY) {
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#ddd"/>`+"\n", f.left(), f.y(v), f.right(), f.y(v))
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n", f.left()-6, f.y(v), tickLabel(v))
+	}
+
+	fmt.Fprintf(b, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="none" stroke="black"/>`+"\n", f.left(), f.top(), f.right()-f.left(), f.bottom()-f.top())
+	fmt.Fprintf(b, `<text x="%.2f" y="%d" text-anchor="middle">%s</text>`+"\n", (f.left()+f.right())/2, f.height-10, escape(c.XLabel))
+	fmt.Fprintf(b, `<text x="16" y="%.2f" text-anchor="middle" transform="rotate(-90 16 %.2f)">%s</text>`+"\n", (f.top()+f.bottom())/2, (f.top()+f.bottom())/2, escape(c.YLabel))
+
+	for i, series := range c.Series {
+		points := make([]string, 0, len(series.X))
+		for j := range series.X {
+			if finite(series.X[j], series.Y[j]) {
+				points = append(points, fmt.Sprintf("%.2f,%.2f", f.x(series.X[j]), f.y(series.Y[j])))
+			}
+		}
+		fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.Join(points, " "), hex(seriesColor(i)))
+
+		legendY := f.top() + 16 + float64(i)*16
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="2"/>`+"\n", f.right()-110, legendY, f.right()-90, legendY, hex(seriesColor(i)))
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" dominant-baseline="middle">%s</text>`+"\n", f.right()-84, legendY, escape(series.Name))
+	}
+
+	fmt.Fprintln(b, `</svg>`)
+
+	return b.Flush()
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,159 @@
+// Package report renders training runs as a self-contained HTML document
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/gregl83/go-binary-classify-nn/infrastructure/plot"
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+// Report of a training run
+type Report struct {
+	Title string
+	// Config used for training
+	Config map[string]interface{}
+	// Parameters trained
+	Parameters lib.Parameters
+	// History of training metrics
+	History lib.History
+	// Evaluation of trained parameters
+	Evaluation lib.Evaluation
+}
+
+// setting of config displayed in report
+type setting struct {
+	Name  string
+	Value string
+}
+
+// layer of network displayed in report
+type layer struct {
+	Index      int
+	Neurons    int
+	Activation string
+	Parameters int
+}
+
+// HTML renders report with charts inlined as SVG
+func (r Report) HTML(w io.Writer) error {
+	charts := []plot.Chart{
+		plot.HistoryChart(r.History, "cost", "cost", "val_cost"),
+		plot.HistoryChart(r.History, "accuracy", "accuracy", "val_accuracy"),
+		plot.ROCChart(r.Evaluation),
+		plot.PRChart(r.Evaluation),
+	}
+
+	svgs := make([]template.HTML, len(charts))
+	for i, chart := range charts {
+		var b bytes.Buffer
+		if err := chart.SVG(&b); err != nil {
+			return err
+		}
+		svgs[i] = template.HTML(b.String())
+	}
+
+	return page.Execute(w, map[string]interface{}{
+		"Title":      r.Title,
+		"Config":     r.settings(),
+		"Layers":     r.layers(),
+		"Charts":     svgs,
+		"Evaluation": r.Evaluation,
+	})
+}
+
+func (r Report) settings() []setting {
+	settings := make([]setting, 0, len(r.Config))
+	for name, value := range r.Config {
+		settings = append(settings, setting{Name: name, Value: fmt.Sprint(value)})
+	}
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Name < settings[j].Name
+	})
+	return settings
+}
+
+func (r Report) layers() []layer {
+	layers := make([]layer, len(r.Parameters.Layers))
+	for i, neurons := range r.Parameters.Layers {
+		layers[i] = layer{Index: i, Neurons: neurons, Activation: r.Parameters.Activation(i)}
+		if i > 0 {
+			layers[i].Parameters = neurons*r.Parameters.Layers[i-1] + neurons
+		}
+	}
+	return layers
+}
+
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(v float64) string {
+		return fmt.Sprintf("%.2f%%", v*100)
+	},
+	"decimal": func(v float64) string {
+		return fmt.Sprintf("%.4f", v)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 1320px; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.charts svg { margin: 0 1em 1em 0; border: 1px solid #eee; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+
+<h2>Configuration</h2>
+<table>
+<tr><th>Setting</th><th>Value</th></tr>
+{{range .Config}}<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+
+<h2>Architecture</h2>
+<table>
+<tr><th>Layer</th><th>Neurons</th><th>Activation</th><th>Parameters</th></tr>
+{{range .Layers}}<tr><td>{{.Index}}</td><td>{{.Neurons}}</td><td>{{.Activation}}</td><td>{{.Parameters}}</td></tr>
+{{end}}</table>
+
+<h2>Curves</h2>
+<div class="charts">
+{{range .Charts}}{{.}}{{end}}
+</div>
+
+{{with .Evaluation}}
+<h2>Evaluation</h2>
+<table>
+<tr><th>Metric</th><th>Value</th></tr>
+<tr><td>Threshold</td><td>{{decimal .Threshold}}</td></tr>
+<tr><td>Accuracy</td><td>{{percent .Accuracy}}</td></tr>
+<tr><td>Precision</td><td>{{percent .Precision}}</td></tr>
+<tr><td>Recall</td><td>{{percent .Recall}}</td></tr>
+<tr><td>F1</td><td>{{decimal .F1}}</td></tr>
+<tr><td>ROC AUC</td><td>{{decimal .ROCAUC}}</td></tr>
+<tr><td>Average Precision</td><td>{{decimal .AveragePrecision}}</td></tr>
+</table>
+
+<h3>Confusion Matrix</h3>
+<table>
+<tr><th></th><th>Predicted Positive</th><th>Predicted Negative</th></tr>
+<tr><td>Actual Positive</td><td>{{.Confusion.TruePositives}}</td><td>{{.Confusion.FalseNegatives}}</td></tr>
+<tr><td>Actual Negative</td><td>{{.Confusion.FalsePositives}}</td><td>{{.Confusion.TrueNegatives}}</td></tr>
+</table>
+
+<h3>Calibration</h3>
+<table>
+<tr><th>Prediction</th><th>Samples</th><th>Mean Prediction</th><th>Positive Rate</th></tr>
+{{range .Calibration}}<tr><td>{{decimal .Lower}} - {{decimal .Upper}}</td><td>{{.Samples}}</td><td>{{decimal .MeanPrediction}}</td><td>{{decimal .PositiveRate}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregl83/go-binary-classify-nn/lib"
+)
+
+func TestHTML(t *testing.T) {
+	report := Report{
+		Title:      "experiment <1>",
+		Config:     map[string]interface{}{"learning_rate": 0.01, "layers": []int{4, 1}},
+		Parameters: lib.NewParameters([]int{3, 4, 1}),
+		History: lib.History{
+			Epochs:  []int{0, 1},
+			Metrics: map[string][]float64{"cost": {0.7, 0.6}},
+		},
+		Evaluation: lib.Evaluation{
+			Confusion: lib.ConfusionMatrix{TruePositives: 7},
+			Calibration: []lib.CalibrationBin{
+				{Lower: 0, Upper: 0.1, Samples: 3},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	assert.NoError(t, report.HTML(&b))
+
+	html := b.String()
+	assert.Contains(t, html, "<title>experiment &lt;1&gt;</title>")
+	assert.Contains(t, html, "<td>layers</td><td>[4 1]</td>")
+	assert.Contains(t, html, "<td>1</td><td>4</td><td>relu</td><td>16</td>")
+	assert.Contains(t, html, "<td>2</td><td>1</td><td>sigmoid</td><td>5</td>")
+	assert.Contains(t, html, "<td>Actual Positive</td><td>7</td>")
+	assert.Equal(t, 4, strings.Count(html, "<svg"))
+}
@@ -0,0 +1,159 @@
+package lib
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// batchNormEpsilon added to variance so units with constant pre-activations are not divided by zero
+	batchNormEpsilon = 1e-5
+	// batchNormMomentum of running statistics, the weight kept of previous running values per batch
+	batchNormMomentum = 0.9
+)
+
+// BatchNorm layer normalizing pre-activations over a batch of samples, applied before their activation
+//
+// Pre-activations of each unit are normalized to zero mean and unit variance then scaled by Gamma and
+// shifted by Beta. Training normalizes with statistics of the batch and updates the running statistics
+// inference normalizes with.
+type BatchNorm struct {
+	// Gamma scale of each unit
+	Gamma mat.Dense
+	// Beta shift of each unit
+	Beta mat.Dense
+	// RunningMean of each unit's pre-activations
+	RunningMean mat.Dense
+	// RunningVariance of each unit's pre-activations
+	RunningVariance mat.Dense
+
+	// normalized pre-activations and inverse standard deviation of each unit in the last training batch
+	normalized mat.Dense
+	inverseStd []float64
+	// gradients of cost with respect to gamma and beta of the last backward pass
+	gammaCostGradients mat.Dense
+	betaCostGradients  mat.Dense
+}
+
+// Forward normalizes, scales and shifts pre-activations with batch statistics when training, otherwise
+// with running statistics
+func (b *BatchNorm) Forward(preActivations *mat.Dense, training bool) mat.Dense {
+	rows, cols := preActivations.Dims()
+	res := mat.NewDense(rows, cols, nil)
+
+	if training {
+		b.normalized = *mat.NewDense(rows, cols, nil)
+		b.inverseStd = make([]float64, rows)
+	}
+
+	for i := 0; i < rows; i++ {
+		row := preActivations.RawRowView(i)
+
+		mean, variance := b.RunningMean.At(i, 0), b.RunningVariance.At(i, 0)
+		if training {
+			mean, variance = meanVariance(row)
+			b.RunningMean.Set(i, 0, batchNormMomentum*b.RunningMean.At(i, 0)+(1-batchNormMomentum)*mean)
+			b.RunningVariance.Set(i, 0, batchNormMomentum*b.RunningVariance.At(i, 0)+(1-batchNormMomentum)*variance)
+		}
+
+		inverseStd := 1 / math.Sqrt(variance+batchNormEpsilon)
+		gamma, beta := b.Gamma.At(i, 0), b.Beta.At(i, 0)
+
+		for j, value := range row {
+			normalized := (value - mean) * inverseStd
+			res.Set(i, j, gamma*normalized+beta)
+			if training {
+				b.normalized.Set(i, j, normalized)
+			}
+		}
+
+		if training {
+			b.inverseStd[i] = inverseStd
+		}
+	}
+
+	return *res
+}
+
+// Backward computes gamma and beta gradients from gradients of cost with respect to normalized outputs of
+// the last training batch returning gradients with respect to pre-activations
+//
+// Gradients are per sample like other pre-activation gradients, gamma and beta gradients are their mean.
+func (b *BatchNorm) Backward(gradients *mat.Dense) mat.Dense {
+	rows, cols := gradients.Dims()
+	res := mat.NewDense(rows, cols, nil)
+	samples := float64(cols)
+
+	b.gammaCostGradients = *mat.NewDense(rows, 1, nil)
+	b.betaCostGradients = *mat.NewDense(rows, 1, nil)
+
+	for i := 0; i < rows; i++ {
+		row, normalized := gradients.RawRowView(i), b.normalized.RawRowView(i)
+		gamma := b.Gamma.At(i, 0)
+
+		var gammaSum, betaSum float64
+		for j, gradient := range row {
+			gammaSum += gradient * normalized[j]
+			betaSum += gradient
+		}
+		b.gammaCostGradients.Set(i, 0, gammaSum/samples)
+		b.betaCostGradients.Set(i, 0, betaSum/samples)
+
+		// gradients with respect to normalized values are gradients scaled by gamma, so their means are too
+		for j, gradient := range row {
+			res.Set(i, j, b.inverseStd[i]*gamma*(gradient-betaSum/samples-normalized[j]*gammaSum/samples))
+		}
+	}
+
+	return *res
+}
+
+// Params are gamma and beta, running statistics are not learned
+func (b *BatchNorm) Params() []*mat.Dense {
+	return []*mat.Dense{&b.Gamma, &b.Beta}
+}
+
+// Grads of gamma and beta
+func (b *BatchNorm) Grads() []*mat.Dense {
+	return []*mat.Dense{&b.gammaCostGradients, &b.betaCostGradients}
+}
+
+// fold running normalization of unit i into a scale and shift of its pre-activations
+func (b *BatchNorm) fold(i int) (float64, float64) {
+	scale := b.Gamma.At(i, 0) / math.Sqrt(b.RunningVariance.At(i, 0)+batchNormEpsilon)
+	return scale, b.Beta.At(i, 0) - scale*b.RunningMean.At(i, 0)
+}
+
+// meanVariance (population) of values
+func meanVariance(values []float64) (float64, float64) {
+	var mean float64
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+
+	return mean, variance / float64(len(values))
+}
+
+// NewBatchNorm of nodes units with unit scale, zero shift and running statistics of a standard normal
+func NewBatchNorm(nodes int) *BatchNorm {
+	gamma := mat.NewDense(nodes, 1, nil)
+	variance := mat.NewDense(nodes, 1, nil)
+	for i := 0; i < nodes; i++ {
+		gamma.Set(i, 0, 1)
+		variance.Set(i, 0, 1)
+	}
+
+	return &BatchNorm{
+		Gamma:           *gamma,
+		Beta:            *mat.NewDense(nodes, 1, nil),
+		RunningMean:     *mat.NewDense(nodes, 1, nil),
+		RunningVariance: *variance,
+	}
+}
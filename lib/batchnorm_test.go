@@ -0,0 +1,103 @@
+package lib
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBatchNormForward(t *testing.T) {
+	batchNorm := NewBatchNorm(2)
+	batchNorm.Gamma.Set(1, 0, 2)
+	batchNorm.Beta.Set(1, 0, 3)
+
+	preActivations := mat.NewDense(2, 4, []float64{
+		1, 2, 3, 4,
+		-1, 0, 1, 2,
+	})
+
+	normalized := batchNorm.Forward(preActivations, true)
+
+	for i, expected := range []struct{ mean, std float64 }{{0, 1}, {3, 2}} {
+		mean, variance := meanVariance(normalized.RawRowView(i))
+		assert.InDelta(t, expected.mean, mean, 1e-9)
+		assert.InDelta(t, expected.std, math.Sqrt(variance), 1e-4) // epsilon
+	}
+
+	// running statistics move a tenth of the way from mean 0 and variance 1 to the batch's 2.5 (0.5) and 1.25
+	assert.InDelta(t, 0.25, batchNorm.RunningMean.At(0, 0), 1e-12)
+	assert.InDelta(t, 0.05, batchNorm.RunningMean.At(1, 0), 1e-12)
+	assert.InDelta(t, 1.025, batchNorm.RunningVariance.At(0, 0), 1e-12)
+
+	inference := batchNorm.Forward(preActivations, false)
+	assert.InDelta(t, (1-0.25)/math.Sqrt(1.025+batchNormEpsilon), inference.At(0, 0), 1e-12)
+	assert.InDelta(t, 0.25, batchNorm.RunningMean.At(0, 0), 1e-12, "inference leaves running statistics")
+}
+
+func TestBatchNormGradients(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	parameters := NewNormalizedParameters([]int{3, 4, 3, 1})
+	parameters.BatchNorms[1].Gamma.Set(2, 0, 1.5)
+	parameters.BatchNorms[2].Beta.Set(0, 0, 0.3)
+	for layer := 1; layer < 4; layer++ {
+		parameters.Weights[layer].Scale(50, &parameters.Weights[layer]) // away from zero so units differ
+	}
+	parameters.Training = true
+	parameters.Activations[0] = *mat.NewDense(3, 6, normRand(18))
+	labels := mat.NewDense(1, 6, []float64{0, 1, 1, 0, 1, 0})
+
+	cost := func() float64 {
+		PropagateForward(&parameters)
+		cost := Cost(parameters.Activations[3].T(), labels.T())
+		return cost.At(0, 0)
+	}
+
+	// numerical derivative of cost with respect to value
+	numerical := func(matrix *mat.Dense, i, j int) float64 {
+		const h = 1e-6
+		value := matrix.At(i, j)
+		matrix.Set(i, j, value+h)
+		plus := cost()
+		matrix.Set(i, j, value-h)
+		minus := cost()
+		matrix.Set(i, j, value)
+		return (plus - minus) / (2 * h)
+	}
+
+	cost()
+	weightCostGradients, _ := PropagateBackward(parameters, labels)
+	gammaCostGradients, betaCostGradients := parameters.BatchNorms[1].gammaCostGradients, parameters.BatchNorms[1].betaCostGradients
+
+	assert.InDelta(t, numerical(&parameters.Weights[1], 2, 1), weightCostGradients[1].At(2, 1), 1e-6)
+	assert.InDelta(t, numerical(&parameters.Weights[2], 0, 3), weightCostGradients[2].At(0, 3), 1e-6)
+	assert.InDelta(t, numerical(&parameters.Weights[3], 0, 2), weightCostGradients[3].At(0, 2), 1e-6)
+	assert.InDelta(t, numerical(&parameters.BatchNorms[1].Gamma, 2, 0), gammaCostGradients.At(2, 0), 1e-6)
+	assert.InDelta(t, numerical(&parameters.BatchNorms[1].Beta, 1, 0), betaCostGradients.At(1, 0), 1e-6)
+}
+
+func TestFold(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	parameters := NewNormalizedParameters([]int{3, 4, 2, 1})
+	for layer := 1; layer < 3; layer++ {
+		batchNorm := parameters.BatchNorms[layer]
+		for i := 0; i < parameters.Layers[layer]; i++ {
+			batchNorm.Gamma.Set(i, 0, 1+rand.Float64())
+			batchNorm.Beta.Set(i, 0, rand.NormFloat64())
+			batchNorm.RunningMean.Set(i, 0, rand.NormFloat64()/10)
+			batchNorm.RunningVariance.Set(i, 0, rand.Float64()/100)
+		}
+	}
+	data := *mat.NewDense(3, 5, normRand(15))
+
+	folded := parameters.Fold()
+	expected, actual := Predict(parameters, data), Predict(folded, data)
+
+	assert.False(t, folded.Normalized())
+	assert.True(t, mat.EqualApprox(&expected, &actual, 1e-12))
+	assert.NotEqual(t, parameters.Weights[1].At(0, 0), folded.Weights[1].At(0, 0), "weights are copied")
+}
@@ -0,0 +1,116 @@
+package lib
+
+// Progress of training passed to callbacks
+type Progress struct {
+	// Epoch of training (zero based)
+	Epoch int
+	// Step is the number of gradient updates completed
+	Step int
+	// Cost of latest step
+	Cost float64
+	// LearningRate applied to gradients
+	LearningRate float64
+	// GradientNorm is the L2 norm of all weight and bias gradients of latest step
+	GradientNorm float64
+	// Metrics of latest epoch by name
+	Metrics map[string]float64
+	// Parameters of network being trained
+	Parameters *Parameters
+
+	stopped bool
+}
+
+// Stop requests training to end at the end of the current epoch
+//
+// The epoch's remaining hooks are still called, and no epoch begins when stopped by OnTrainBegin.
+func (p *Progress) Stop() {
+	p.stopped = true
+}
+
+// Stopped reports whether a callback requested training to end
+func (p *Progress) Stopped() bool {
+	return p.stopped
+}
+
+// Callback hooks observing the training loop
+type Callback interface {
+	// OnTrainBegin is called before the first epoch
+	OnTrainBegin(progress *Progress)
+	// OnEpochBegin is called before each epoch
+	OnEpochBegin(progress *Progress)
+	// OnBatchEnd is called after each gradient update
+	OnBatchEnd(progress *Progress)
+	// OnEpochEnd is called after each epoch with metrics populated
+	OnEpochEnd(progress *Progress)
+	// OnTrainEnd is called once training completes, stops or is cancelled
+	OnTrainEnd(progress *Progress)
+}
+
+// BaseCallback implements Callback with no-op hooks so callbacks can embed it and override hooks of interest
+type BaseCallback struct{}
+
+// OnTrainBegin does nothing
+func (BaseCallback) OnTrainBegin(progress *Progress) {}
+
+// OnEpochBegin does nothing
+func (BaseCallback) OnEpochBegin(progress *Progress) {}
+
+// OnBatchEnd does nothing
+func (BaseCallback) OnBatchEnd(progress *Progress) {}
+
+// OnEpochEnd does nothing
+func (BaseCallback) OnEpochEnd(progress *Progress) {}
+
+// OnTrainEnd does nothing
+func (BaseCallback) OnTrainEnd(progress *Progress) {}
+
+// ParametersObserver is implemented by callbacks reading Progress.Parameters at the end of each epoch
+//
+// Trainers keeping parameters in another form, such as Model32, only convert them for Progress.Parameters
+// every epoch when a callback observes them.
+type ParametersObserver interface {
+	ObservesParameters()
+}
+
+// callbacks dispatches hooks to each callback in order
+type callbacks []Callback
+
+// observesParameters reports whether any callback is a ParametersObserver
+func (c callbacks) observesParameters() bool {
+	for _, callback := range c {
+		if _, ok := callback.(ParametersObserver); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c callbacks) OnTrainBegin(progress *Progress) {
+	for _, callback := range c {
+		callback.OnTrainBegin(progress)
+	}
+}
+
+func (c callbacks) OnEpochBegin(progress *Progress) {
+	for _, callback := range c {
+		callback.OnEpochBegin(progress)
+	}
+}
+
+func (c callbacks) OnBatchEnd(progress *Progress) {
+	for _, callback := range c {
+		callback.OnBatchEnd(progress)
+	}
+}
+
+func (c callbacks) OnEpochEnd(progress *Progress) {
+	for _, callback := range c {
+		callback.OnEpochEnd(progress)
+	}
+}
+
+func (c callbacks) OnTrainEnd(progress *Progress) {
+	for _, callback := range c {
+		callback.OnTrainEnd(progress)
+	}
+}
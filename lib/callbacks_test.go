@@ -0,0 +1,140 @@
+package lib
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+type recordingCallback struct {
+	BaseCallback
+	hooks  []string
+	stopAt int
+	// stopBatch stops from OnBatchEnd rather than OnEpochEnd
+	stopBatch bool
+}
+
+func (c *recordingCallback) OnTrainBegin(progress *Progress) {
+	c.hooks = append(c.hooks, "trainBegin")
+}
+
+func (c *recordingCallback) OnEpochBegin(progress *Progress) {
+	c.hooks = append(c.hooks, "epochBegin")
+}
+
+func (c *recordingCallback) OnBatchEnd(progress *Progress) {
+	c.hooks = append(c.hooks, "batchEnd")
+	if c.stopBatch && progress.Epoch == c.stopAt {
+		progress.Stop()
+	}
+}
+
+func (c *recordingCallback) OnEpochEnd(progress *Progress) {
+	c.hooks = append(c.hooks, "epochEnd")
+	if !c.stopBatch && progress.Epoch == c.stopAt {
+		progress.Stop()
+	}
+}
+
+func (c *recordingCallback) OnTrainEnd(progress *Progress) {
+	c.hooks = append(c.hooks, "trainEnd")
+}
+
+func TestCallbacks(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	data := mat.NewDense(2, 4, []float64{
+		0, 0, 1, 1,
+		0, 1, 0, 1,
+	})
+
+	labels := mat.NewDense(1, 4, []float64{
+		0, 1, 1, 1,
+	})
+
+	callback := &recordingCallback{stopAt: 1}
+
+	_, costs := Model(context.Background(), *data, *labels, []int{2, 1}, 0.5, 100, callback)
+
+	assert.Len(t, costs, 2)
+	assert.Equal(t, []string{
+		"trainBegin",
+		"epochBegin", "batchEnd", "epochEnd",
+		"epochBegin", "batchEnd", "epochEnd",
+		"trainEnd",
+	}, callback.hooks)
+}
+
+func TestCallbacksStopEndsEpoch(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	data := mat.NewDense(2, 4, []float64{
+		0, 0, 1, 1,
+		0, 1, 0, 1,
+	})
+
+	labels := mat.NewDense(1, 4, []float64{
+		0, 1, 1, 1,
+	})
+
+	for name, train := range map[string]func(callback Callback) []float64{
+		"float64": func(callback Callback) []float64 {
+			_, costs := Model(context.Background(), *data, *labels, []int{2, 1}, 0.5, 100, callback)
+			return costs
+		},
+		"float32": func(callback Callback) []float64 {
+			_, costs := Model32(context.Background(), *data, *labels, []int{2, 1}, 0.5, 100, callback)
+			return costs
+		},
+	} {
+		callback := &recordingCallback{stopAt: 0, stopBatch: true}
+
+		costs := train(callback)
+
+		assert.Len(t, costs, 1, name)
+		assert.Equal(t, []string{
+			"trainBegin",
+			"epochBegin", "batchEnd", "epochEnd",
+			"trainEnd",
+		}, callback.hooks, name)
+	}
+}
+
+func TestCallbacksProgress(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	data := mat.NewDense(2, 4, []float64{
+		0, 0, 1, 1,
+		0, 1, 0, 1,
+	})
+
+	labels := mat.NewDense(1, 4, []float64{
+		0, 1, 1, 1,
+	})
+
+	var progress *Progress
+	callback := &progressCallback{onEpochEnd: func(p *Progress) {
+		progress = p
+	}}
+
+	_, costs := Model(context.Background(), *data, *labels, []int{2, 1}, 0.5, 3, callback)
+
+	assert.Equal(t, 2, progress.Epoch)
+	assert.Equal(t, 3, progress.Step)
+	assert.Equal(t, costs[2], progress.Cost)
+	assert.Equal(t, costs[2], progress.Metrics["cost"])
+	assert.Contains(t, progress.Metrics, "accuracy")
+	assert.Equal(t, []int{2, 1}, progress.Parameters.Layers)
+}
+
+type progressCallback struct {
+	BaseCallback
+	onEpochEnd func(progress *Progress)
+}
+
+func (c *progressCallback) OnEpochEnd(progress *Progress) {
+	c.onEpochEnd(progress)
+}
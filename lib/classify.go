@@ -0,0 +1,61 @@
+package lib
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Prediction of a sample
+type Prediction struct {
+	// Score is the output activation of the network
+	Score float64 `json:"score"`
+	// Label classified from score using Threshold
+	Label int `json:"label"`
+}
+
+// Classify samples, each a slice of features, in a single batched forward pass returning predictions in order
+func Classify(parameters Parameters, samples [][]float64) ([]Prediction, error) {
+	if err := parameters.Validate(0); err != nil {
+		return nil, err
+	}
+
+	return classifySamples(parameters.Layers[0], func(data mat.Dense) mat.Dense {
+		return Predict(parameters, data)
+	}, samples)
+}
+
+// Classify samples like Classify predicting with int8 arithmetic
+func (q *QuantizedParameters) Classify(samples [][]float64) ([]Prediction, error) {
+	if err := q.Validate(0); err != nil {
+		return nil, err
+	}
+
+	return classifySamples(q.Layers[0], q.Predict, samples)
+}
+
+// classifySamples of features each in a single call to predict
+func classifySamples(features int, predict func(data mat.Dense) mat.Dense, samples [][]float64) ([]Prediction, error) {
+	if len(samples) == 0 {
+		return []Prediction{}, nil
+	}
+
+	data := mat.NewDense(features, len(samples), nil)
+
+	for j, sample := range samples {
+		if len(sample) != features {
+			return nil, fmt.Errorf("sample %d has %d features, expected %d", j, len(sample), features)
+		}
+		data.SetCol(j, sample)
+	}
+
+	activations := predict(*data)
+
+	predictions := make([]Prediction, len(samples))
+	for j := range predictions {
+		score := activations.At(0, j)
+		predictions[j] = Prediction{Score: score, Label: int(classify(score, Threshold))}
+	}
+
+	return predictions, nil
+}
@@ -0,0 +1,59 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestClassify(t *testing.T) {
+	parameters := NewParameters([]int{2, 1})
+	parameters.Weights[1] = *mat.NewDense(1, 2, []float64{1, 1})
+	parameters.Bias[1] = *mat.NewDense(1, 1, []float64{-1})
+
+	predictions, err := Classify(parameters, [][]float64{{0, 0}, {2, 2}, {0.5, 0.5}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Prediction{
+		{Score: sigmoid(-1), Label: 0},
+		{Score: sigmoid(3), Label: 1},
+		{Score: 0.5, Label: 1},
+	}, predictions)
+}
+
+func TestClassifyFeatureMismatch(t *testing.T) {
+	parameters := NewParameters([]int{2, 1})
+
+	_, err := Classify(parameters, [][]float64{{0, 0}, {1}})
+
+	assert.EqualError(t, err, "sample 1 has 1 features, expected 2")
+}
+
+func TestClassifyInvalidParameters(t *testing.T) {
+	_, err := Classify(Parameters{}, [][]float64{{0, 0}})
+
+	assert.EqualError(t, err, "parameters require at least 2 layers, got 0")
+}
+
+func TestQuantizedClassify(t *testing.T) {
+	parameters := NewParameters([]int{2, 1})
+	parameters.Weights[1] = *mat.NewDense(1, 2, []float64{1, 1})
+	parameters.Bias[1] = *mat.NewDense(1, 1, []float64{-1})
+	quantized := Quantize(parameters, *mat.NewDense(2, 2, []float64{2, 0, 2, 0}), true)
+
+	expected, err := Classify(parameters, [][]float64{{2, 2}, {0, 0}})
+	assert.NoError(t, err)
+
+	predictions, err := quantized.Classify([][]float64{{2, 2}, {0, 0}})
+	assert.NoError(t, err)
+	assert.Equal(t, expected[0].Label, predictions[0].Label)
+	assert.Equal(t, expected[1].Label, predictions[1].Label)
+	assert.InDelta(t, expected[0].Score, predictions[0].Score, 1e-2)
+
+	_, err = quantized.Classify([][]float64{{1}})
+	assert.EqualError(t, err, "sample 0 has 1 features, expected 2")
+
+	_, err = (&QuantizedParameters{}).Classify([][]float64{{1}})
+	assert.EqualError(t, err, "quantized parameters require at least 2 layers, got 0")
+}
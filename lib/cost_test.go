@@ -35,4 +35,13 @@ func TestCostNotRegularized(t *testing.T) {
 //	for i := 0; i < len(expected); i++ {
 //		assert.Equal(t, expected[i], cost.RawRowView(i))
 //	}
-//}
\ No newline at end of file
+//}
+
+func BenchmarkCost(b *testing.B) {
+	benchmark(b, func(b *testing.B, parameters Parameters, data, labels mat.Dense) {
+		predictions := &parameters.Activations[len(parameters.Layers)-1]
+		for i := 0; i < b.N; i++ {
+			Cost(predictions.T(), labels.T())
+		}
+	})
+}
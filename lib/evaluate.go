@@ -0,0 +1,214 @@
+package lib
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// calibrationBins of equal width predictions are grouped into
+const calibrationBins = 10
+
+// Point on a curve computed at a prediction threshold
+type Point struct {
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Threshold float64 `json:"threshold"`
+}
+
+// pointJSON of Point, a nil threshold is NaN
+type pointJSON struct {
+	X         float64  `json:"x"`
+	Y         float64  `json:"y"`
+	Threshold *float64 `json:"threshold"`
+}
+
+// MarshalJSON encodes the NaN threshold of diverged predictions as null
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pointJSON{X: p.X, Y: p.Y, Threshold: finite(p.Threshold)})
+}
+
+// UnmarshalJSON decodes a null threshold as NaN
+func (p *Point) UnmarshalJSON(b []byte) error {
+	var record pointJSON
+	if err := json.Unmarshal(b, &record); err != nil {
+		return err
+	}
+
+	*p = Point{X: record.X, Y: record.Y, Threshold: orNaN(record.Threshold)}
+	return nil
+}
+
+// ConfusionMatrix of classified samples
+type ConfusionMatrix struct {
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	TrueNegatives  int `json:"true_negatives"`
+	FalseNegatives int `json:"false_negatives"`
+}
+
+// CalibrationBin of samples with predictions within [Lower, Upper)
+type CalibrationBin struct {
+	Lower   float64 `json:"lower"`
+	Upper   float64 `json:"upper"`
+	Samples int     `json:"samples"`
+	// MeanPrediction of samples in bin
+	MeanPrediction float64 `json:"mean_prediction"`
+	// PositiveRate of samples in bin labelled positive
+	PositiveRate float64 `json:"positive_rate"`
+}
+
+// Evaluation of predictions against labels
+type Evaluation struct {
+	// Threshold predictions are classified with
+	Threshold float64 `json:"threshold"`
+	// Confusion of predictions classified at threshold
+	Confusion ConfusionMatrix `json:"confusion"`
+	Accuracy  float64         `json:"accuracy"`
+	Precision float64         `json:"precision"`
+	Recall    float64         `json:"recall"`
+	F1        float64         `json:"f1"`
+	// ROC curve of false positive rate (X) to true positive rate (Y)
+	ROC []Point `json:"roc"`
+	// ROCAUC is the area under the ROC curve
+	ROCAUC float64 `json:"roc_auc"`
+	// PR curve of recall (X) to precision (Y)
+	PR []Point `json:"pr"`
+	// AveragePrecision summarizing the PR curve
+	AveragePrecision float64 `json:"average_precision"`
+	// Calibration of predictions to observed positive rate in equal width bins
+	Calibration []CalibrationBin `json:"calibration"`
+}
+
+// Evaluate predictions and labels, each a row of samples, classifying with threshold
+func Evaluate(predictions, labels mat.Matrix, threshold float64) Evaluation {
+	_, samples := predictions.Dims()
+
+	scores := make([]float64, samples)
+	actual := make([]bool, samples)
+	var positives, negatives int
+	for j := 0; j < samples; j++ {
+		scores[j] = predictions.At(0, j)
+		actual[j] = labels.At(0, j) == 1
+		if actual[j] {
+			positives++
+		} else {
+			negatives++
+		}
+	}
+
+	evaluation := Evaluation{Threshold: threshold}
+
+	for j, score := range scores {
+		predicted := classify(score, threshold) == 1
+		switch {
+		case predicted && actual[j]:
+			evaluation.Confusion.TruePositives++
+		case predicted:
+			evaluation.Confusion.FalsePositives++
+		case actual[j]:
+			evaluation.Confusion.FalseNegatives++
+		default:
+			evaluation.Confusion.TrueNegatives++
+		}
+	}
+
+	confusion := evaluation.Confusion
+	evaluation.Accuracy = ratio(confusion.TruePositives+confusion.TrueNegatives, samples)
+	evaluation.Precision = ratio(confusion.TruePositives, confusion.TruePositives+confusion.FalsePositives)
+	evaluation.Recall = ratio(confusion.TruePositives, positives)
+	if evaluation.Precision+evaluation.Recall > 0 {
+		evaluation.F1 = 2 * evaluation.Precision * evaluation.Recall / (evaluation.Precision + evaluation.Recall)
+	}
+
+	// sweep thresholds from highest to lowest score, NaN scores of diverged predictions last as they are
+	// never classified positive
+	order := make([]int, samples)
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := scores[order[a]], scores[order[b]]
+		return x > y || !math.IsNaN(x) && math.IsNaN(y)
+	})
+
+	evaluation.ROC = []Point{{X: 0, Y: 0, Threshold: 1}}
+	evaluation.PR = []Point{{X: 0, Y: 1, Threshold: 1}}
+
+	var truePositives, falsePositives int
+	for k, j := range order {
+		if actual[j] {
+			truePositives++
+		} else {
+			falsePositives++
+		}
+
+		// emit a point once all samples sharing a score are counted
+		if k+1 < samples && sameScore(scores[order[k+1]], scores[j]) {
+			continue
+		}
+
+		roc := Point{X: ratio(falsePositives, negatives), Y: ratio(truePositives, positives), Threshold: scores[j]}
+		pr := Point{X: roc.Y, Y: ratio(truePositives, truePositives+falsePositives), Threshold: scores[j]}
+
+		previousROC := evaluation.ROC[len(evaluation.ROC)-1]
+		evaluation.ROCAUC += (roc.X - previousROC.X) * (roc.Y + previousROC.Y) / 2
+
+		previousPR := evaluation.PR[len(evaluation.PR)-1]
+		evaluation.AveragePrecision += (pr.X - previousPR.X) * pr.Y
+
+		evaluation.ROC = append(evaluation.ROC, roc)
+		evaluation.PR = append(evaluation.PR, pr)
+	}
+
+	evaluation.Calibration = calibrate(scores, actual, calibrationBins)
+
+	return evaluation
+}
+
+func calibrate(scores []float64, actual []bool, bins int) []CalibrationBin {
+	calibration := make([]CalibrationBin, bins)
+	positives := make([]int, bins)
+
+	for i := range calibration {
+		calibration[i].Lower = float64(i) / float64(bins)
+		calibration[i].Upper = float64(i+1) / float64(bins)
+	}
+
+	for j, score := range scores {
+		if math.IsNaN(score) {
+			continue // diverged predictions belong to no bin
+		}
+
+		// clamped before conversion as converting out of range floats to int is undefined
+		bin := int(math.Max(0, math.Min(score*float64(bins), float64(bins-1))))
+		calibration[bin].Samples++
+		calibration[bin].MeanPrediction += score
+		if actual[j] {
+			positives[bin]++
+		}
+	}
+
+	for i := range calibration {
+		if calibration[i].Samples > 0 {
+			calibration[i].MeanPrediction /= float64(calibration[i].Samples)
+		}
+		calibration[i].PositiveRate = ratio(positives[i], calibration[i].Samples)
+	}
+
+	return calibration
+}
+
+// sameScore of x and y, NaN scores are the same as each other
+func sameScore(x, y float64) bool {
+	return x == y || math.IsNaN(x) && math.IsNaN(y)
+}
+
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
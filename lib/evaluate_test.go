@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestEvaluate(t *testing.T) {
+	predictions := mat.NewDense(1, 4, []float64{0.9, 0.8, 0.3, 0.1})
+	labels := mat.NewDense(1, 4, []float64{1, 0, 1, 0})
+
+	evaluation := Evaluate(predictions, labels, 0.5)
+
+	assert.Equal(t, ConfusionMatrix{
+		TruePositives:  1,
+		FalsePositives: 1,
+		TrueNegatives:  1,
+		FalseNegatives: 1,
+	}, evaluation.Confusion)
+	assert.Equal(t, 0.5, evaluation.Accuracy)
+	assert.Equal(t, 0.5, evaluation.Precision)
+	assert.Equal(t, 0.5, evaluation.Recall)
+	assert.Equal(t, 0.5, evaluation.F1)
+
+	assert.Equal(t, []Point{
+		{X: 0, Y: 0, Threshold: 1},
+		{X: 0, Y: 0.5, Threshold: 0.9},
+		{X: 0.5, Y: 0.5, Threshold: 0.8},
+		{X: 0.5, Y: 1, Threshold: 0.3},
+		{X: 1, Y: 1, Threshold: 0.1},
+	}, evaluation.ROC)
+	assert.Equal(t, 0.75, evaluation.ROCAUC)
+
+	assert.Equal(t, []Point{
+		{X: 0, Y: 1, Threshold: 1},
+		{X: 0.5, Y: 1, Threshold: 0.9},
+		{X: 0.5, Y: 0.5, Threshold: 0.8},
+		{X: 1, Y: 2.0 / 3, Threshold: 0.3},
+		{X: 1, Y: 0.5, Threshold: 0.1},
+	}, evaluation.PR)
+	assert.InDelta(t, 0.5+0.5*2.0/3, evaluation.AveragePrecision, 1e-12)
+
+	assert.Len(t, evaluation.Calibration, 10)
+	assert.Equal(t, CalibrationBin{Lower: 0.1, Upper: 0.2, Samples: 1, MeanPrediction: 0.1, PositiveRate: 0}, evaluation.Calibration[1])
+	assert.Equal(t, CalibrationBin{Lower: 0.9, Upper: 1, Samples: 1, MeanPrediction: 0.9, PositiveRate: 1}, evaluation.Calibration[9])
+}
+
+func TestEvaluateTiedScores(t *testing.T) {
+	predictions := mat.NewDense(1, 4, []float64{0.5, 0.5, 0.5, 0.5})
+	labels := mat.NewDense(1, 4, []float64{1, 0, 1, 0})
+
+	evaluation := Evaluate(predictions, labels, 0.5)
+
+	assert.Len(t, evaluation.ROC, 2)
+	assert.Equal(t, 0.5, evaluation.ROCAUC)
+}
+
+func TestEvaluateOutOfRangeScores(t *testing.T) {
+	predictions := mat.NewDense(1, 4, []float64{math.NaN(), -0.01, 1.5, 0.95})
+	labels := mat.NewDense(1, 4, []float64{1, 0, 1, 1})
+
+	evaluation := Evaluate(predictions, labels, 0.5)
+
+	var samples int
+	for _, bin := range evaluation.Calibration {
+		samples += bin.Samples
+	}
+	assert.Equal(t, 3, samples, "NaN scores are skipped")
+	assert.Equal(t, 1, evaluation.Calibration[0].Samples)
+	assert.Equal(t, 2, evaluation.Calibration[9].Samples)
+}
+
+func TestEvaluateNaNScores(t *testing.T) {
+	predictions := mat.NewDense(1, 5, []float64{math.NaN(), 0.9, math.NaN(), 0.2, 0.6})
+	labels := mat.NewDense(1, 5, []float64{1, 1, 0, 0, 1})
+
+	evaluation := Evaluate(predictions, labels, 0.5)
+
+	thresholds := make([]float64, len(evaluation.ROC))
+	for i, point := range evaluation.ROC {
+		thresholds[i] = point.Threshold
+	}
+	assert.Equal(t, []float64{1, 0.9, 0.6, 0.2}, thresholds[:4])
+	assert.Len(t, thresholds, 5, "NaN scores form a single last point")
+	assert.True(t, math.IsNaN(thresholds[4]))
+	assert.Equal(t, 1.0, evaluation.ROC[4].X)
+
+	b, err := json.Marshal(evaluation)
+	assert.NoError(t, err)
+
+	var read Evaluation
+	assert.NoError(t, json.Unmarshal(b, &read))
+	assert.Equal(t, evaluation.ROC[:4], read.ROC[:4])
+	assert.True(t, math.IsNaN(read.PR[4].Threshold))
+}
@@ -0,0 +1,312 @@
+package lib
+
+import (
+	"context"
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas32"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Parameters32 are single precision weights and bias of each layer of a network
+//
+// Built once by NewParameters32, with batch normalization folded in, so predictions neither convert weights
+// nor require float64 parameters be kept. Predict is safe for concurrent use.
+type Parameters32 struct {
+	// Layers of network, neurons per layer including input features
+	Layers  []int
+	weights []blas32.General
+	bias    [][]float32
+}
+
+// Predict computes output layer activations for data like Predict computing in single precision
+func (p *Parameters32) Predict(data mat.Dense) mat.Dense {
+	layers := len(p.Layers)
+	preActivations, activations := make([]blas32.General, layers), make([]blas32.General, layers)
+	activations[0] = toGeneral32(&data)
+
+	p.forward(preActivations, activations)
+
+	return fromGeneral32(activations[layers-1])
+}
+
+// forward computes neuron activations for each network layer from input layer activations
+func (p *Parameters32) forward(preActivations, activations []blas32.General) {
+	for layer := 1; layer < len(p.Layers); layer++ {
+		previous := activations[layer-1]
+		weights := p.weights[layer]
+
+		z := newGeneral32(weights.Rows, previous.Cols)
+		blas32.Gemm(blas.NoTrans, blas.NoTrans, 1, weights, previous, 0, z)
+
+		a := newGeneral32(z.Rows, z.Cols)
+		for i := 0; i < z.Rows; i++ {
+			for j := 0; j < z.Cols; j++ {
+				value := z.Data[i*z.Stride+j] + p.bias[layer][i]
+				z.Data[i*z.Stride+j] = value
+				a.Data[i*a.Stride+j] = activate32(activation(len(p.Layers), layer), value)
+			}
+		}
+
+		preActivations[layer], activations[layer] = z, a
+	}
+}
+
+// parameters32 are single precision parameters and activations of each layer trained by Model32
+type parameters32 struct {
+	Parameters32
+	preActivations []blas32.General
+	activations    []blas32.General
+}
+
+// Model32 trains a neural network like Model computing in single precision
+//
+// Data, labels and returned parameters are float64, only computation and memory held during training are
+// float32. Parameters are converted to float64 when training ends and, only when a callback is a
+// ParametersObserver, at the end of each epoch. Like a Workspace, layers are fused dense loops rather than
+// Layer implementations.
+func Model32(ctx context.Context, data, labels mat.Dense, layers []int, learningRate float64, iterations int, callback ...Callback) (Parameters, []float64) {
+	costs := make([]float64, 0, iterations)
+	parameters := toParameters32(NewParameters(layers))
+	lastLayer := len(layers) - 1
+	hooks := callbacks(callback)
+	observed := hooks.observesParameters()
+
+	parameters.activations[0] = toGeneral32(&data)
+	y := toGeneral32(&labels)
+
+	current := parameters.parameters()
+	progress := &Progress{Parameters: &current, LearningRate: learningRate}
+	hooks.OnTrainBegin(progress)
+
+	for i := 0; i < iterations && !progress.Stopped(); i++ {
+		select {
+		case <-ctx.Done():
+			current = parameters.parameters()
+			hooks.OnTrainEnd(progress)
+			return current, costs
+		default:
+		}
+
+		progress.Epoch = i
+		hooks.OnEpochBegin(progress)
+
+		parameters.forward()
+
+		costs = append(costs, cost32(parameters.activations[lastLayer], y))
+
+		weightCostGradients, biasCostGradients := parameters.backward(y)
+
+		parameters.update(weightCostGradients, biasCostGradients, float32(learningRate))
+
+		if observed {
+			current = parameters.parameters()
+		}
+
+		progress.Step++
+		progress.Cost = costs[i]
+		progress.GradientNorm = gradientNorm32(weightCostGradients, biasCostGradients)
+		hooks.OnBatchEnd(progress)
+
+		progress.Metrics = map[string]float64{
+			"cost":     costs[i],
+			"accuracy": accuracy32(parameters.activations[lastLayer], y, Threshold),
+		}
+		hooks.OnEpochEnd(progress)
+	}
+
+	current = parameters.parameters()
+	hooks.OnTrainEnd(progress)
+
+	return current, costs
+}
+
+// Predict32 computes output layer activations for data like Predict computing in single precision
+//
+// Batch normalization is folded into weights and bias. Parameters are converted on every call, predicting
+// repeatedly should use Parameters32 built once with NewParameters32.
+func Predict32(parameters Parameters, data mat.Dense) mat.Dense {
+	return NewParameters32(parameters).Predict(data)
+}
+
+// forward computes neuron activations for each network layer
+func (p *parameters32) forward() {
+	p.Parameters32.forward(p.preActivations, p.activations)
+}
+
+// backward computes gradients of cost with respect to weights and bias of each layer
+func (p *parameters32) backward(labels blas32.General) ([]blas32.General, [][]float32) {
+	layers := len(p.Layers)
+	lastLayer := layers - 1
+	samples := float32(labels.Cols)
+
+	weightCostGradients := make([]blas32.General, layers)
+	biasCostGradients := make([][]float32, layers)
+
+	output := p.activations[lastLayer]
+	activationCostGradients := newGeneral32(output.Rows, output.Cols)
+	for k, a := range output.Data {
+		y := labels.Data[k]
+		activationCostGradients.Data[k] = -(y/a - (1-y)/(1-a))
+	}
+
+	for layer := lastLayer; layer > 0; layer-- {
+		z := p.preActivations[layer]
+		previous := p.activations[layer-1]
+		name := activation(layers, layer)
+
+		preActivationCostGradients := newGeneral32(z.Rows, z.Cols)
+		for k, value := range z.Data {
+			preActivationCostGradients.Data[k] = activatePrime32(name, activationCostGradients.Data[k], value)
+		}
+
+		weightCostGradients[layer] = newGeneral32(z.Rows, previous.Rows)
+		blas32.Gemm(blas.NoTrans, blas.Trans, 1/samples, preActivationCostGradients, previous, 0, weightCostGradients[layer])
+
+		biasCostGradients[layer] = make([]float32, z.Rows)
+		for i := 0; i < z.Rows; i++ {
+			var sum float32
+			for _, value := range preActivationCostGradients.Data[i*z.Stride : i*z.Stride+z.Cols] {
+				sum += value
+			}
+			biasCostGradients[layer][i] = sum / samples
+		}
+
+		activationCostGradients = newGeneral32(previous.Rows, previous.Cols)
+		blas32.Gemm(blas.Trans, blas.NoTrans, 1, p.weights[layer], preActivationCostGradients, 0, activationCostGradients)
+	}
+
+	return weightCostGradients, biasCostGradients
+}
+
+// update weights and bias with gradients scaled by learning rate
+func (p *parameters32) update(weightCostGradients []blas32.General, biasCostGradients [][]float32, learningRate float32) {
+	for layer := 1; layer < len(p.Layers); layer++ {
+		for k, gradient := range weightCostGradients[layer].Data {
+			p.weights[layer].Data[k] -= learningRate * gradient
+		}
+		for i, gradient := range biasCostGradients[layer] {
+			p.bias[layer][i] -= learningRate * gradient
+		}
+	}
+}
+
+// parameters converted to double precision
+func (p *parameters32) parameters() Parameters {
+	layers := len(p.Layers)
+	parameters := Parameters{
+		Layers:         p.Layers,
+		Weights:        make([]mat.Dense, layers),
+		Bias:           make([]mat.Dense, layers),
+		PreActivations: make([]mat.Dense, layers),
+		Activations:    make([]mat.Dense, layers),
+	}
+
+	for layer := 1; layer < layers; layer++ {
+		parameters.Weights[layer] = fromGeneral32(p.weights[layer])
+		parameters.Bias[layer] = fromGeneral32(blas32.General{Rows: len(p.bias[layer]), Cols: 1, Stride: 1, Data: p.bias[layer]})
+	}
+
+	return parameters
+}
+
+// NewParameters32 converts parameters to single precision folding batch normalization into weights and bias
+func NewParameters32(parameters Parameters) *Parameters32 {
+	parameters = parameters.Fold()
+	layers := len(parameters.Layers)
+	p := &Parameters32{
+		Layers:  parameters.Layers,
+		weights: make([]blas32.General, layers),
+		bias:    make([][]float32, layers),
+	}
+
+	for layer := 1; layer < layers; layer++ {
+		p.weights[layer] = toGeneral32(&parameters.Weights[layer])
+		p.bias[layer] = toGeneral32(&parameters.Bias[layer]).Data
+	}
+
+	return p
+}
+
+func toParameters32(parameters Parameters) parameters32 {
+	layers := len(parameters.Layers)
+	return parameters32{
+		Parameters32:   *NewParameters32(parameters),
+		preActivations: make([]blas32.General, layers),
+		activations:    make([]blas32.General, layers),
+	}
+}
+
+func newGeneral32(rows, cols int) blas32.General {
+	return blas32.General{Rows: rows, Cols: cols, Stride: cols, Data: make([]float32, rows*cols)}
+}
+
+func toGeneral32(matrix mat.Matrix) blas32.General {
+	rows, cols := matrix.Dims()
+	res := newGeneral32(rows, cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			res.Data[i*res.Stride+j] = float32(matrix.At(i, j))
+		}
+	}
+	return res
+}
+
+func fromGeneral32(matrix blas32.General) mat.Dense {
+	res := mat.NewDense(matrix.Rows, matrix.Cols, nil)
+	for i := 0; i < matrix.Rows; i++ {
+		for j := 0; j < matrix.Cols; j++ {
+			res.Set(i, j, float64(matrix.Data[i*matrix.Stride+j]))
+		}
+	}
+	return *res
+}
+
+func activate32(name string, z float32) float32 {
+	if name == "sigmoid" {
+		return float32(sigmoid(float64(z)))
+	}
+	return float32(relu(float64(z)))
+}
+
+func activatePrime32(name string, x, z float32) float32 {
+	if name == "sigmoid" {
+		return float32(sigmoidPrime(float64(x), float64(z)))
+	}
+	return float32(reluPrime(float64(x), float64(z)))
+}
+
+// cost32 (cross-entropy) of single precision predictions to labels accumulated in double precision
+func cost32(predictions, labels blas32.General) float64 {
+	var sum float64
+	for k, a := range predictions.Data {
+		y, a := float64(labels.Data[k]), float64(a)
+		sum += y*math.Log(a) + (1-y)*math.Log(1-a)
+	}
+	return -sum / float64(len(predictions.Data))
+}
+
+// accuracy32 of single precision predictions to labels classified with threshold
+func accuracy32(predictions, labels blas32.General, threshold float64) float64 {
+	var correct int
+	for k, a := range predictions.Data {
+		if classify(float64(a), threshold) == float64(labels.Data[k]) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(predictions.Data))
+}
+
+func gradientNorm32(weightCostGradients []blas32.General, biasCostGradients [][]float32) float64 {
+	var sum float64
+	for layer := range weightCostGradients {
+		for _, gradient := range weightCostGradients[layer].Data {
+			sum += float64(gradient) * float64(gradient)
+		}
+		for _, gradient := range biasCostGradients[layer] {
+			sum += float64(gradient) * float64(gradient)
+		}
+	}
+	return math.Sqrt(sum)
+}
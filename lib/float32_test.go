@@ -0,0 +1,111 @@
+package lib
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestModel32(t *testing.T) {
+	data := mat.NewDense(2, 4, []float64{
+		0, 0, 1, 1,
+		0, 1, 0, 1,
+	})
+
+	labels := mat.NewDense(1, 4, []float64{
+		0, 1, 1, 1,
+	})
+
+	rand.Seed(1) // identical initialization
+	expected, expectedCosts := Model(context.Background(), *data, *labels, []int{2, 3, 1}, 0.5, 200)
+
+	rand.Seed(1)
+	actual, actualCosts := Model32(context.Background(), *data, *labels, []int{2, 3, 1}, 0.5, 200)
+
+	assert.Len(t, actualCosts, 200)
+	assert.InDeltaSlice(t, expectedCosts, actualCosts, 1e-4)
+
+	for layer := 1; layer < len(expected.Layers); layer++ {
+		assert.True(t, mat.EqualApprox(&expected.Weights[layer], &actual.Weights[layer], 1e-4))
+		assert.True(t, mat.EqualApprox(&expected.Bias[layer], &actual.Bias[layer], 1e-4))
+	}
+}
+
+func TestModel32Cancelled(t *testing.T) {
+	data := mat.NewDense(2, 2, []float64{0, 1, 1, 0})
+	labels := mat.NewDense(1, 2, []float64{0, 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	parameters, costs := Model32(ctx, *data, *labels, []int{2, 1}, 0.5, 100)
+
+	assert.Len(t, costs, 0)
+	assert.Equal(t, []int{2, 1}, parameters.Layers)
+}
+
+func TestPredict32(t *testing.T) {
+	parameters := NewParameters([]int{3, 4, 1})
+	parameters.Weights[1] = *mat.NewDense(4, 3, []float64{
+		0.8, -0.4, 0.3,
+		-0.6, 0.9, 0.1,
+		0.2, 0.5, -0.7,
+		-0.3, -0.2, 0.6,
+	})
+	parameters.Bias[1] = *mat.NewDense(4, 1, []float64{0.1, -0.2, 0.05, 0})
+	parameters.Weights[2] = *mat.NewDense(1, 4, []float64{1.5, -1.2, 0.8, -0.5})
+	parameters.Bias[2] = *mat.NewDense(1, 1, []float64{-0.1})
+
+	data := *mat.NewDense(3, 2, []float64{1, -1, 0.1, 0.2, -0.7, 0.4})
+
+	expected := Predict(parameters, data)
+	actual := Predict32(parameters, data)
+
+	assert.True(t, mat.EqualApprox(&expected, &actual, 1e-6))
+
+	parameters32 := NewParameters32(parameters)
+	actual = parameters32.Predict(data)
+
+	assert.Equal(t, []int{3, 4, 1}, parameters32.Layers)
+	assert.True(t, mat.EqualApprox(&expected, &actual, 1e-6))
+}
+
+func TestParameters32BatchNorm(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	parameters := NewNormalizedParameters([]int{3, 4, 1})
+	parameters.BatchNorms[1].RunningMean.Set(0, 0, 0.2)
+	parameters.BatchNorms[1].Gamma.Set(1, 0, 1.5)
+	data := *mat.NewDense(3, 2, []float64{1, -1, 0.1, 0.2, -0.7, 0.4})
+
+	expected := Predict(parameters, data)
+	actual := NewParameters32(parameters).Predict(data)
+
+	assert.True(t, mat.EqualApprox(&expected, &actual, 1e-6))
+}
+
+func TestModel32ObservesParameters(t *testing.T) {
+	data := mat.NewDense(2, 2, []float64{0, 1, 1, 0})
+	labels := mat.NewDense(1, 2, []float64{0, 1})
+
+	recorder := &parametersRecorder{}
+	Model32(context.Background(), *data, *labels, []int{2, 1}, 0.5, 3, recorder)
+
+	assert.Len(t, recorder.weights, 3)
+	assert.NotEqual(t, recorder.weights[0], recorder.weights[2], "parameters are converted each epoch")
+}
+
+// parametersRecorder observing parameters at the end of each epoch
+type parametersRecorder struct {
+	BaseCallback
+	weights []float64
+}
+
+func (r *parametersRecorder) OnEpochEnd(progress *Progress) {
+	r.weights = append(r.weights, progress.Parameters.Weights[1].At(0, 0))
+}
+
+func (r *parametersRecorder) ObservesParameters() {}
@@ -82,6 +82,20 @@ func columnBroadcast(bias mat.Matrix, cols int) mat.Dense {
 	return *res
 }
 
+func gradientNorm(weightCostGradients, biasCostGradients []mat.Dense) float64 {
+	var sum float64
+	for layer := range weightCostGradients {
+		for _, gradients := range []*mat.Dense{&weightCostGradients[layer], &biasCostGradients[layer]} {
+			if rows, _ := gradients.Dims(); rows == 0 {
+				continue
+			}
+			norm := mat.Norm(gradients, 2)
+			sum += norm * norm
+		}
+	}
+	return math.Sqrt(sum)
+}
+
 func normRand(len int) []float64 {
 	res := make([]float64, len)
 
@@ -0,0 +1,127 @@
+package lib
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// History callback recording metrics at the end of each epoch
+type History struct {
+	BaseCallback
+	// Epochs recorded
+	Epochs []int `json:"epochs"`
+	// Metrics by name with a value per recorded epoch
+	Metrics MetricHistory `json:"metrics"`
+}
+
+// OnEpochEnd records epoch metrics
+func (h *History) OnEpochEnd(progress *Progress) {
+	h.Epochs = append(h.Epochs, progress.Epoch)
+	for name, value := range progress.Metrics {
+		h.Metrics[name] = append(h.Metrics[name], value)
+	}
+}
+
+// MetricHistory of values by metric name
+//
+// JSON has no representation of NaN or infinity, such as costs of a diverged run, so they are encoded as
+// null and decoded as NaN.
+type MetricHistory map[string][]float64
+
+// MarshalJSON encodes non-finite values as null
+func (m MetricHistory) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	record := make(map[string][]*float64, len(m))
+	for name, values := range m {
+		record[name] = make([]*float64, len(values))
+		for i, value := range values {
+			record[name][i] = finite(value)
+		}
+	}
+
+	return json.Marshal(record)
+}
+
+// UnmarshalJSON decodes null values as NaN
+func (m *MetricHistory) UnmarshalJSON(b []byte) error {
+	var record map[string][]*float64
+	if err := json.Unmarshal(b, &record); err != nil {
+		return err
+	}
+
+	if record == nil {
+		*m = nil
+		return nil
+	}
+
+	*m = make(MetricHistory, len(record))
+	for name, values := range record {
+		(*m)[name] = make([]float64, len(values))
+		for i, value := range values {
+			(*m)[name][i] = orNaN(value)
+		}
+	}
+
+	return nil
+}
+
+// Metrics by name of a single epoch, non-finite values are encoded like MetricHistory
+type Metrics map[string]float64
+
+// MarshalJSON encodes non-finite values as null
+func (m Metrics) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	record := make(map[string]*float64, len(m))
+	for name, value := range m {
+		record[name] = finite(value)
+	}
+
+	return json.Marshal(record)
+}
+
+// UnmarshalJSON decodes null values as NaN
+func (m *Metrics) UnmarshalJSON(b []byte) error {
+	var record map[string]*float64
+	if err := json.Unmarshal(b, &record); err != nil {
+		return err
+	}
+
+	if record == nil {
+		*m = nil
+		return nil
+	}
+
+	*m = make(Metrics, len(record))
+	for name, value := range record {
+		(*m)[name] = orNaN(value)
+	}
+
+	return nil
+}
+
+// finite value or nil when value is NaN or infinite
+func finite(value float64) *float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return &value
+}
+
+// orNaN of value, NaN when nil
+func orNaN(value *float64) float64 {
+	if value == nil {
+		return math.NaN()
+	}
+	return *value
+}
+
+// NewHistory callback
+func NewHistory() *History {
+	return &History{Metrics: MetricHistory{}}
+}
@@ -0,0 +1,46 @@
+package lib
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	history := NewHistory()
+
+	history.OnEpochEnd(&Progress{Epoch: 0, Metrics: map[string]float64{"cost": 0.7}})
+	history.OnEpochEnd(&Progress{Epoch: 1, Metrics: map[string]float64{"cost": 0.6}})
+
+	assert.Equal(t, []int{0, 1}, history.Epochs)
+	assert.Equal(t, []float64{0.7, 0.6}, history.Metrics["cost"])
+}
+
+func TestHistoryJSONNonFinite(t *testing.T) {
+	history := NewHistory()
+	history.OnEpochEnd(&Progress{Epoch: 0, Metrics: map[string]float64{"cost": 0.7}})
+	history.OnEpochEnd(&Progress{Epoch: 1, Metrics: map[string]float64{"cost": math.Inf(1)}})
+	history.OnEpochEnd(&Progress{Epoch: 2, Metrics: map[string]float64{"cost": math.NaN()}})
+
+	b, err := json.Marshal(history)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"epochs": [0, 1, 2], "metrics": {"cost": [0.7, null, null]}}`, string(b))
+
+	var read History
+	assert.NoError(t, json.Unmarshal(b, &read))
+	assert.Equal(t, 0.7, read.Metrics["cost"][0])
+	assert.True(t, math.IsNaN(read.Metrics["cost"][2]))
+}
+
+func TestMetricsJSONNonFinite(t *testing.T) {
+	b, err := json.Marshal(Metrics{"cost": math.NaN(), "accuracy": 0.5})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"cost": null, "accuracy": 0.5}`, string(b))
+
+	var read Metrics
+	assert.NoError(t, json.Unmarshal(b, &read))
+	assert.Equal(t, 0.5, read["accuracy"])
+	assert.True(t, math.IsNaN(read["cost"]))
+}
@@ -0,0 +1,147 @@
+package lib
+
+import (
+	"encoding/json"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Statistics summarizing a set of values
+type Statistics struct {
+	Mean float64 `json:"mean"`
+	Std  float64 `json:"std"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+// statisticsJSON of Statistics, nil values are not finite
+type statisticsJSON struct {
+	Mean *float64 `json:"mean"`
+	Std  *float64 `json:"std"`
+	Min  *float64 `json:"min"`
+	Max  *float64 `json:"max"`
+}
+
+// MarshalJSON encodes non-finite statistics, of diverged parameters, as null
+func (s Statistics) MarshalJSON() ([]byte, error) {
+	return json.Marshal(statisticsJSON{Mean: finite(s.Mean), Std: finite(s.Std), Min: finite(s.Min), Max: finite(s.Max)})
+}
+
+// LayerInspection of a layer's shape, activation and parameter statistics
+type LayerInspection struct {
+	Layer      int        `json:"layer"`
+	Inputs     int        `json:"inputs"`
+	Units      int        `json:"units"`
+	Activation string     `json:"activation"`
+	Parameters int        `json:"parameters"`
+	Weights    Statistics `json:"weights"`
+	Bias       Statistics `json:"bias"`
+	// BatchNorm applied to pre-activations, its gamma and beta counted in parameters
+	BatchNorm bool `json:"batch_norm"`
+	// DeadFraction of relu units never activated by inspected data, nil when not measured
+	DeadFraction *float64 `json:"dead_fraction,omitempty"`
+}
+
+// Inspection of neural network parameters
+//
+// Parameters do not hold the threshold or feature scaling they are used with, those are recorded in model
+// metadata.
+type Inspection struct {
+	Layers     []int             `json:"layers"`
+	Parameters int               `json:"parameters"`
+	Summaries  []LayerInspection `json:"summaries"`
+}
+
+// Inspect parameters summarizing each layer after the input layer
+func Inspect(parameters Parameters) Inspection {
+	inspection := Inspection{Layers: parameters.Layers}
+
+	for layer := 1; layer < len(parameters.Layers); layer++ {
+		weights, bias := &parameters.Weights[layer], &parameters.Bias[layer]
+		rows, cols := weights.Dims()
+
+		summary := LayerInspection{
+			Layer:      layer,
+			Inputs:     cols,
+			Units:      rows,
+			Activation: parameters.Activation(layer),
+			Parameters: rows*cols + rows,
+			Weights:    summarize(weights),
+			Bias:       summarize(bias),
+		}
+
+		if parameters.batchNorm(layer) != nil {
+			summary.BatchNorm = true
+			summary.Parameters += 2 * rows
+		}
+
+		inspection.Parameters += summary.Parameters
+		inspection.Summaries = append(inspection.Summaries, summary)
+	}
+
+	return inspection
+}
+
+// DeadUnits measures the fraction of relu units per layer that output zero for every sample of data
+func (i *Inspection) DeadUnits(parameters Parameters, data mat.Dense) {
+	layers := len(parameters.Layers)
+
+	parameters.PreActivations = make([]mat.Dense, layers)
+	parameters.Activations = make([]mat.Dense, layers)
+	parameters.Activations[0] = data
+
+	PropagateForward(&parameters)
+
+	for s := range i.Summaries {
+		summary := &i.Summaries[s]
+		if summary.Activation != "relu" {
+			continue
+		}
+
+		activations := &parameters.Activations[summary.Layer]
+		rows, _ := activations.Dims()
+
+		var dead int
+		for row := 0; row < rows; row++ {
+			if mat.Max(activations.RowView(row)) <= 0 {
+				dead++
+			}
+		}
+
+		fraction := float64(dead) / float64(rows)
+		summary.DeadFraction = &fraction
+	}
+}
+
+func summarize(m mat.Matrix) Statistics {
+	rows, cols := m.Dims()
+	n := float64(rows * cols)
+
+	if n == 0 {
+		return Statistics{}
+	}
+
+	statistics := Statistics{Min: math.Inf(1), Max: math.Inf(-1)}
+
+	var sum float64
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			value := m.At(i, j)
+			sum += value
+			statistics.Min = math.Min(statistics.Min, value)
+			statistics.Max = math.Max(statistics.Max, value)
+		}
+	}
+	statistics.Mean = sum / n
+
+	var squares float64
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			squares += math.Pow(m.At(i, j)-statistics.Mean, 2)
+		}
+	}
+	statistics.Std = math.Sqrt(squares / n)
+
+	return statistics
+}
@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInspect(t *testing.T) {
+	parameters := NewParameters([]int{2, 2, 1})
+	parameters.Weights[1] = *mat.NewDense(2, 2, []float64{1, -1, -2, -3})
+	parameters.Bias[1] = *mat.NewDense(2, 1, []float64{0, 1})
+	parameters.Weights[2] = *mat.NewDense(1, 2, []float64{2, 4})
+	parameters.Bias[2] = *mat.NewDense(1, 1, []float64{-1})
+
+	inspection := Inspect(parameters)
+
+	assert.Equal(t, []int{2, 2, 1}, inspection.Layers)
+	assert.Equal(t, 9, inspection.Parameters)
+	assert.Equal(t, []LayerInspection{
+		{
+			Layer:      1,
+			Inputs:     2,
+			Units:      2,
+			Activation: "relu",
+			Parameters: 6,
+			Weights:    Statistics{Mean: -1.25, Std: 1.479019945774904, Min: -3, Max: 1},
+			Bias:       Statistics{Mean: 0.5, Std: 0.5, Min: 0, Max: 1},
+		},
+		{
+			Layer:      2,
+			Inputs:     2,
+			Units:      1,
+			Activation: "sigmoid",
+			Parameters: 3,
+			Weights:    Statistics{Mean: 3, Std: 1, Min: 2, Max: 4},
+			Bias:       Statistics{Mean: -1, Std: 0, Min: -1, Max: -1},
+		},
+	}, inspection.Summaries)
+}
+
+func TestStatisticsJSONNonFinite(t *testing.T) {
+	b, err := json.Marshal(Statistics{Mean: math.NaN(), Std: math.NaN(), Min: math.Inf(-1), Max: 2})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"mean": null, "std": null, "min": null, "max": 2}`, string(b))
+}
+
+func TestInspectionDeadUnits(t *testing.T) {
+	parameters := NewParameters([]int{2, 2, 1})
+	parameters.Weights[1] = *mat.NewDense(2, 2, []float64{1, 1, -1, -1})
+	parameters.Bias[1] = *mat.NewDense(2, 1, []float64{0, 0})
+
+	inspection := Inspect(parameters)
+	inspection.DeadUnits(parameters, *mat.NewDense(2, 2, []float64{1, 2, 3, 4}))
+
+	assert.Equal(t, 0.5, *inspection.Summaries[0].DeadFraction)
+	assert.Nil(t, inspection.Summaries[1].DeadFraction)
+}
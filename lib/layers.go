@@ -0,0 +1,251 @@
+package lib
+
+import (
+	"fmt"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Layer of a neural network transforming inputs, a column per sample, to outputs
+//
+// Gradients follow PropagateBackward: gradients with respect to inputs and outputs are per sample, gradients
+// of parameters are means over samples.
+//
+// PropagateForward and PropagateBackward pass parameters through Dense, BatchNorm and Activation layers. Other
+// layers, such as Dropout, are composed into networks with Sequential and trained with Sequential.Update;
+// Model only trains the layers of Parameters, and its Workspace and Model32 keep fused dense loops.
+type Layer interface {
+	// Forward computes outputs of inputs keeping what Backward requires, training selects batch behaviour
+	// of layers such as Dropout and BatchNorm
+	Forward(inputs *mat.Dense, training bool) mat.Dense
+	// Backward computes gradients of cost with respect to inputs of the last Forward from gradients with
+	// respect to its outputs, keeping gradients of parameters
+	Backward(gradients *mat.Dense) mat.Dense
+	// Params of layer updated by gradient descent
+	Params() []*mat.Dense
+	// Grads of cost with respect to Params computed by the last Backward
+	Grads() []*mat.Dense
+}
+
+// Dense layer computing weights times inputs plus bias
+type Dense struct {
+	// Weights applied to each input per unit
+	Weights *mat.Dense
+	// Bias applied to each unit
+	Bias *mat.Dense
+
+	inputs              mat.Dense
+	weightCostGradients mat.Dense
+	biasCostGradients   mat.Dense
+}
+
+// Forward computes pre-activations of inputs
+func (d *Dense) Forward(inputs *mat.Dense, training bool) mat.Dense {
+	d.inputs = *inputs
+	return linearForward(inputs, d.Weights, d.Bias)
+}
+
+// Backward computes gradients with respect to inputs, weights and bias
+func (d *Dense) Backward(gradients *mat.Dense) mat.Dense {
+	var inputCostGradients mat.Dense
+	inputCostGradients, d.weightCostGradients, d.biasCostGradients = linearBackward(gradients, &d.inputs, d.Weights, d.Bias)
+	return inputCostGradients
+}
+
+// Params are weights and bias
+func (d *Dense) Params() []*mat.Dense {
+	return []*mat.Dense{d.Weights, d.Bias}
+}
+
+// Grads of weights and bias
+func (d *Dense) Grads() []*mat.Dense {
+	return []*mat.Dense{&d.weightCostGradients, &d.biasCostGradients}
+}
+
+// Activation layer applying an activation function, relu or sigmoid, to each input
+//
+// NewActivation rejects unsupported names, Forward and Backward panic on them.
+type Activation struct {
+	// Name of activation function
+	Name string
+
+	preActivations mat.Dense
+}
+
+// Forward computes activations of pre-activations
+func (a *Activation) Forward(inputs *mat.Dense, training bool) mat.Dense {
+	a.preActivations = *inputs
+	function, _ := a.functions()
+	return activate(inputs, function)
+}
+
+// Backward computes gradients with respect to pre-activations
+func (a *Activation) Backward(gradients *mat.Dense) mat.Dense {
+	_, prime := a.functions()
+	return activatePrime(gradients, &a.preActivations, prime)
+}
+
+// functions of activation and derivative
+func (a *Activation) functions() (func(x float64) float64, func(x, z float64) float64) {
+	function, prime, err := activationFunctions(a.Name)
+	if err != nil {
+		panic(err)
+	}
+	return function, prime
+}
+
+// Params are none
+func (a *Activation) Params() []*mat.Dense {
+	return nil
+}
+
+// Grads are none
+func (a *Activation) Grads() []*mat.Dense {
+	return nil
+}
+
+// Dropout layer zeroing each input with probability Rate while training
+//
+// Kept inputs are scaled by 1 / (1 - Rate) so expected outputs match inference, where inputs pass through.
+type Dropout struct {
+	// Rate inputs are dropped at
+	Rate float64
+
+	mask mat.Dense
+}
+
+// Forward drops inputs when training
+func (d *Dropout) Forward(inputs *mat.Dense, training bool) mat.Dense {
+	if !training {
+		d.mask = mat.Dense{}
+		return *mat.DenseCopyOf(inputs)
+	}
+
+	rows, cols := inputs.Dims()
+	d.mask = *mat.NewDense(rows, cols, nil)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if rand.Float64() >= d.Rate {
+				d.mask.Set(i, j, 1/(1-d.Rate))
+			}
+		}
+	}
+
+	var res mat.Dense
+	res.MulElem(inputs, &d.mask)
+	return res
+}
+
+// Backward passes gradients of kept inputs
+func (d *Dropout) Backward(gradients *mat.Dense) mat.Dense {
+	if rows, _ := d.mask.Dims(); rows == 0 {
+		return *mat.DenseCopyOf(gradients)
+	}
+
+	var res mat.Dense
+	res.MulElem(gradients, &d.mask)
+	return res
+}
+
+// Params are none
+func (d *Dropout) Params() []*mat.Dense {
+	return nil
+}
+
+// Grads are none
+func (d *Dropout) Grads() []*mat.Dense {
+	return nil
+}
+
+// Sequential container of layers, each layer's outputs the inputs of the next
+type Sequential struct {
+	Layers []Layer
+}
+
+// Forward computes outputs of the last layer
+func (s *Sequential) Forward(inputs *mat.Dense, training bool) mat.Dense {
+	outputs := *inputs
+	for _, layer := range s.Layers {
+		outputs = layer.Forward(&outputs, training)
+	}
+	return outputs
+}
+
+// Backward computes gradients with respect to inputs of the first layer
+func (s *Sequential) Backward(gradients *mat.Dense) mat.Dense {
+	res := *gradients
+	for l := len(s.Layers) - 1; l >= 0; l-- {
+		res = s.Layers[l].Backward(&res)
+	}
+	return res
+}
+
+// Params of each layer in order
+func (s *Sequential) Params() []*mat.Dense {
+	var params []*mat.Dense
+	for _, layer := range s.Layers {
+		params = append(params, layer.Params()...)
+	}
+	return params
+}
+
+// Grads of each layer in order
+func (s *Sequential) Grads() []*mat.Dense {
+	var grads []*mat.Dense
+	for _, layer := range s.Layers {
+		grads = append(grads, layer.Grads()...)
+	}
+	return grads
+}
+
+// Update params of layers using gradients of the last Backward and learning rate
+func (s *Sequential) Update(learningRate float64) {
+	update(s, learningRate)
+}
+
+// update layer params in place using its grads and learning rate
+func update(layer Layer, learningRate float64) {
+	grads := layer.Grads()
+	for i, param := range layer.Params() {
+		descend(param, grads[i], learningRate)
+	}
+}
+
+// activationFunctions of name and their derivatives
+func activationFunctions(name string) (func(x float64) float64, func(x, z float64) float64, error) {
+	switch name {
+	case "relu":
+		return relu, reluPrime, nil
+	case "sigmoid":
+		return sigmoid, sigmoidPrime, nil
+	default:
+		return nil, nil, fmt.Errorf("activation %q not supported", name)
+	}
+}
+
+// NewDense layer of units with initialized weights for inputs
+func NewDense(inputs, units int) *Dense {
+	weights := mat.NewDense(units, inputs, normRand(inputs*units))
+	weights.Scale(0.01, weights)
+
+	return &Dense{Weights: weights, Bias: mat.NewDense(units, 1, nil)}
+}
+
+// NewActivation layer of activation function name, relu or sigmoid
+func NewActivation(name string) (*Activation, error) {
+	if _, _, err := activationFunctions(name); err != nil {
+		return nil, err
+	}
+	return &Activation{Name: name}, nil
+}
+
+// NewDropout layer dropping inputs at rate
+func NewDropout(rate float64) *Dropout {
+	return &Dropout{Rate: rate}
+}
+
+// NewSequential container of layers
+func NewSequential(layers ...Layer) *Sequential {
+	return &Sequential{Layers: layers}
+}
@@ -0,0 +1,398 @@
+package lib
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestParametersSequential(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	parameters := NewNormalizedParameters([]int{3, 4, 2, 1})
+	parameters.Training = true
+	data := *mat.NewDense(3, 5, normRand(15))
+	labels := mat.NewDense(1, 5, []float64{1, 0, 0, 1, 1})
+
+	network := parameters.Sequential()
+	assert.Len(t, network.Layers, 8) // dense, batch norm and activation per hidden layer, dense and activation
+	assert.Len(t, network.Params(), 10)
+
+	output := network.Forward(&data, true)
+
+	var activationCostGradients mat.Dense
+	activationCostGradients.Apply(func(i, j int, a float64) float64 {
+		y := labels.At(i, j)
+		return -(y/a - (1-y)/(1-a))
+	}, &output)
+	network.Backward(&activationCostGradients)
+
+	var grads []mat.Dense
+	for _, grad := range network.Grads() {
+		grads = append(grads, *mat.DenseCopyOf(grad))
+	}
+
+	parameters.Activations[0] = data
+	PropagateForward(&parameters)
+	weightCostGradients, biasCostGradients := PropagateBackward(parameters, labels)
+
+	assert.True(t, mat.EqualApprox(&parameters.Activations[3], &output, 1e-12))
+	assert.True(t, mat.EqualApprox(&weightCostGradients[1], &grads[0], 1e-12))
+	assert.True(t, mat.EqualApprox(&biasCostGradients[1], &grads[1], 1e-12))
+	assert.True(t, mat.EqualApprox(&parameters.BatchNorms[2].gammaCostGradients, &grads[6], 1e-12))
+	assert.True(t, mat.EqualApprox(&weightCostGradients[3], &grads[8], 1e-12))
+}
+
+func TestSequential(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	data := mat.NewDense(2, 4, []float64{
+		0, 0, 1, 1,
+		0, 1, 0, 1,
+	})
+
+	labels := mat.NewDense(1, 4, []float64{
+		0, 1, 1, 0,
+	})
+
+	network := NewSequential(
+		NewDense(2, 8),
+		NewBatchNorm(8),
+		newActivation(t, "relu"),
+		NewDropout(0.1),
+		NewDense(8, 1),
+		newActivation(t, "sigmoid"),
+	)
+
+	var costs []float64
+	for i := 0; i < 300; i++ {
+		output := network.Forward(data, true)
+		cost := Cost(output.T(), labels.T())
+		costs = append(costs, cost.At(0, 0))
+
+		var gradients mat.Dense
+		gradients.Apply(func(i, j int, a float64) float64 {
+			y := labels.At(i, j)
+			return -(y/a - (1-y)/(1-a))
+		}, &output)
+
+		network.Backward(&gradients)
+		network.Update(0.5)
+	}
+
+	assert.True(t, costs[len(costs)-1] < costs[0])
+
+	predictions := network.Forward(data, false)
+	rows, cols := predictions.Dims()
+	assert.Equal(t, 1, rows)
+	assert.Equal(t, 4, cols)
+}
+
+func TestDenseActivationForwardSigmoid(t *testing.T) {
+	expected := map[string][][]float64{
+		"preActivations": {
+			{
+				3.4389613356978117,
+				-2.0893843586662904,
+			},
+		},
+		"activations": {
+			{
+				0.9689002334527027,
+				0.11013289497444277,
+			},
+		},
+	}
+
+	previousActivations := mat.NewDense(3, 2, []float64{
+		-0.41675785,
+		-0.05626683,
+		-2.1361961,
+		1.64027081,
+		-1.79343559,
+		-0.84174737,
+	})
+
+	weights := mat.NewDense(1, 3, []float64{
+		0.50288142,
+		-1.24528809,
+		-1.05795222,
+	})
+
+	bias := mat.NewDense(1, 1, []float64{
+		-0.90900761,
+	})
+
+	dense, activation := &Dense{Weights: weights, Bias: bias}, newActivation(t, "sigmoid")
+	preActivations := dense.Forward(previousActivations, false)
+	activations := activation.Forward(&preActivations, false)
+
+	for i := 0; i < len(expected["preActivations"]); i++ {
+		assert.Equal(t, expected["preActivations"][i], preActivations.RawRowView(i))
+	}
+
+	for i := 0; i < len(expected["activations"]); i++ {
+		assert.Equal(t, expected["activations"][i], activations.RawRowView(i))
+	}
+}
+
+func TestDenseActivationForwardRelu(t *testing.T) {
+	expected := map[string][][]float64{
+		"preActivations": {
+			{
+				3.4389613356978117,
+				-2.0893843586662904,
+			},
+		},
+		"activations": {
+			{
+				3.4389613356978117,
+				0,
+			},
+		},
+	}
+
+	previousActivations := mat.NewDense(3, 2, []float64{
+		-0.41675785,
+		-0.05626683,
+		-2.1361961,
+		1.64027081,
+		-1.79343559,
+		-0.84174737,
+	})
+
+	weights := mat.NewDense(1, 3, []float64{
+		0.50288142,
+		-1.24528809,
+		-1.05795222,
+	})
+
+	bias := mat.NewDense(1, 1, []float64{
+		-0.90900761,
+	})
+
+	dense, activation := &Dense{Weights: weights, Bias: bias}, newActivation(t, "relu")
+	preActivations := dense.Forward(previousActivations, false)
+	activations := activation.Forward(&preActivations, false)
+
+	for i := 0; i < len(expected["preActivations"]); i++ {
+		assert.Equal(t, expected["preActivations"][i], preActivations.RawRowView(i))
+	}
+
+	for i := 0; i < len(expected["activations"]); i++ {
+		assert.Equal(t, expected["activations"][i], activations.RawRowView(i))
+	}
+}
+
+func TestDenseActivationBackwardRelu(t *testing.T) {
+	expected := map[string][][]float64{
+		"previousActivationCostGradients": {
+			{
+				0.44090989260992697,
+				0,
+			},
+			{
+				0.37883605717723845,
+				0,
+			},
+			{
+				-0.229822800084214,
+				0,
+			},
+		},
+		"weightCostGradients": {
+			{
+				0.44513824690719245,
+				0.3737141803009408,
+				-0.10478988970207351,
+			},
+		},
+		"biasCostGradients": {
+			{
+				-0.208378925,
+			},
+		},
+	}
+
+	activationCostGradients := mat.NewDense(1, 2, []float64{
+		-0.41675785,
+		-0.05626683,
+	})
+
+	preActivations := mat.NewDense(1, 2, []float64{
+		0.04153939,
+		-1.11792545,
+	})
+
+	previousActivations := mat.NewDense(3,2, []float64{
+		-2.1361961,
+		1.64027081,
+		-1.79343559,
+		-0.84174737,
+		0.50288142,
+		-1.24528809,
+	})
+
+	weights := mat.NewDense(1, 3, []float64{
+		-1.05795222,
+		-0.90900761,
+		0.55145404,
+	})
+
+	bias := mat.NewDense(1, 1, []float64{
+		2.29220801,
+	})
+
+	dense, activation := &Dense{Weights: weights, Bias: bias}, newActivation(t, "relu")
+	dense.Forward(previousActivations, false)
+	activation.Forward(preActivations, false)
+
+	preActivationCostGradients := activation.Backward(activationCostGradients)
+	previousActivationCostGradients := dense.Backward(&preActivationCostGradients)
+	grads := dense.Grads()
+	weightCostGradients, biasCostGradients := grads[0], grads[1]
+
+	for i := 0; i < len(expected["previousActivationCostGradients"]); i++ {
+		assert.Equal(t, expected["previousActivationCostGradients"][i], previousActivationCostGradients.RawRowView(i))
+	}
+
+	for i := 0; i < len(expected["weightCostGradients"]); i++ {
+		assert.Equal(t, expected["weightCostGradients"][i], weightCostGradients.RawRowView(i))
+	}
+
+	for i := 0; i < len(expected["biasCostGradients"]); i++ {
+		assert.Equal(t, expected["biasCostGradients"][i], biasCostGradients.RawRowView(i))
+	}
+}
+
+func TestDenseActivationBackwardSigmoid(t *testing.T) {
+	expected := map[string][][]float64{
+		"previousActivationCostGradients": {
+			{
+				0.11017993687111528,
+				0.01105339523719341,
+			},
+			{
+				0.09466817044456259,
+				0.009497234560315553,
+			},
+			{
+				-0.057430921894111135,
+				-0.005761545128443573,
+			},
+		},
+		"weightCostGradients": {
+			{
+				0.10266786428377704,
+				0.09778550606902146,
+				-0.019680842328738218,
+			},
+		},
+		"biasCostGradients": {
+			{
+				-0.05729622274827718,
+			},
+		},
+	}
+
+	activationCostGradients := mat.NewDense(1, 2, []float64{
+		-0.41675785,
+		-0.05626683,
+	})
+
+	preActivations := mat.NewDense(1, 2, []float64{
+		0.04153939,
+		-1.11792545,
+	})
+
+	previousActivations := mat.NewDense(3,2, []float64{
+		-2.1361961,
+		1.64027081,
+		-1.79343559,
+		-0.84174737,
+		0.50288142,
+		-1.24528809,
+	})
+
+	weights := mat.NewDense(1, 3, []float64{
+		-1.05795222,
+		-0.90900761,
+		0.55145404,
+	})
+
+	bias := mat.NewDense(1, 1, []float64{
+		2.29220801,
+	})
+
+	dense, activation := &Dense{Weights: weights, Bias: bias}, newActivation(t, "sigmoid")
+	dense.Forward(previousActivations, false)
+	activation.Forward(preActivations, false)
+
+	preActivationCostGradients := activation.Backward(activationCostGradients)
+	previousActivationCostGradients := dense.Backward(&preActivationCostGradients)
+	grads := dense.Grads()
+	weightCostGradients, biasCostGradients := grads[0], grads[1]
+
+	for i := 0; i < len(expected["previousActivationCostGradients"]); i++ {
+		assert.Equal(t, expected["previousActivationCostGradients"][i], previousActivationCostGradients.RawRowView(i))
+	}
+
+	for i := 0; i < len(expected["weightCostGradients"]); i++ {
+		assert.Equal(t, expected["weightCostGradients"][i], weightCostGradients.RawRowView(i))
+	}
+
+	for i := 0; i < len(expected["biasCostGradients"]); i++ {
+		assert.Equal(t, expected["biasCostGradients"][i], biasCostGradients.RawRowView(i))
+	}
+}
+
+func TestDropout(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	dropout := NewDropout(0.5)
+	inputs := mat.NewDense(10, 100, nil)
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 100; j++ {
+			inputs.Set(i, j, 1)
+		}
+	}
+
+	outputs := dropout.Forward(inputs, true)
+
+	var kept int
+	for i := 0; i < 10; i++ {
+		for _, value := range outputs.RawRowView(i) {
+			assert.Contains(t, []float64{0, 2}, value)
+			if value > 0 {
+				kept++
+			}
+		}
+	}
+	assert.InDelta(t, 500, kept, 60)
+
+	gradients := dropout.Backward(inputs)
+	assert.True(t, mat.Equal(&outputs, &gradients), "gradients of dropped inputs are dropped")
+
+	outputs = dropout.Forward(inputs, false)
+	assert.True(t, mat.Equal(inputs, &outputs), "inference passes inputs through")
+}
+
+// newActivation layer of supported activation function name
+func newActivation(t *testing.T, name string) *Activation {
+	activation, err := NewActivation(name)
+	assert.NoError(t, err)
+	return activation
+}
+
+func TestNewActivation(t *testing.T) {
+	activation, err := NewActivation("relu")
+	assert.NoError(t, err)
+	assert.Equal(t, "relu", activation.Name)
+
+	_, err = NewActivation("tanh")
+	assert.EqualError(t, err, `activation "tanh" not supported`)
+
+	assert.Panics(t, func() { (&Activation{Name: "tanh"}).Forward(mat.NewDense(1, 1, nil), false) })
+}
@@ -0,0 +1,28 @@
+package lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Accuracy of predictions classified using threshold against labels
+func Accuracy(predictions, labels mat.Matrix, threshold float64) float64 {
+	rows, cols := predictions.Dims()
+
+	var correct int
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if classify(predictions.At(i, j), threshold) == labels.At(i, j) {
+				correct++
+			}
+		}
+	}
+
+	return float64(correct) / float64(rows*cols)
+}
+
+func classify(prediction, threshold float64) float64 {
+	if prediction >= threshold {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestAccuracy(t *testing.T) {
+	predictions := mat.NewDense(1, 4, []float64{0.9, 0.2, 0.5, 0.4})
+	labels := mat.NewDense(1, 4, []float64{1, 0, 0, 1})
+
+	assert.Equal(t, 0.5, Accuracy(predictions, labels, 0.5))
+}
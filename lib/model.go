@@ -1,24 +1,112 @@
 package lib
 
 import (
+	"context"
+
 	"gonum.org/v1/gonum/mat"
 )
 
-func Model(data, labels mat.Dense, layers []int, learningRate float64, iterations int) (Parameters, []float64) {
-	costs := make([]float64, iterations)
-	parameters := NewParameters(layers)
+// Threshold of output activation at or above which a sample is classified positive
+const Threshold = 0.5
+
+// Options of training a neural network
+type Options struct {
+	// Workers goroutines each batch is split across, less than 1 uses a worker per CPU
+	Workers int
+	// BatchNorm normalizes pre-activations of hidden layers, batches are then computed by a single worker
+	// as statistics are of the whole batch
+	BatchNorm bool
+}
+
+// Model trains a neural network using gradient descent returning parameters and cost per iteration
+//
+// Training stops early when ctx is cancelled or a callback requests it; the current step is completed and
+// only the costs of completed iterations are returned. Each iteration is an epoch of a single full batch
+// computed in a Workspace, so steps reuse the same buffers rather than allocating.
+func Model(ctx context.Context, data, labels mat.Dense, layers []int, learningRate float64, iterations int, callback ...Callback) (Parameters, []float64) {
+	return ModelOptions(ctx, data, labels, layers, learningRate, iterations, Options{Workers: 1}, callback...)
+}
+
+// ModelParallel trains a neural network like Model splitting each batch across workers goroutines
+//
+// Each worker passes forward and backward a shard of samples and shard gradients are reduced before the
+// parameters are updated, so results match Model within floating-point tolerance. Workers less than 1
+// uses a worker per CPU.
+func ModelParallel(ctx context.Context, data, labels mat.Dense, layers []int, learningRate float64, iterations, workers int, callback ...Callback) (Parameters, []float64) {
+	return ModelOptions(ctx, data, labels, layers, learningRate, iterations, Options{Workers: workers}, callback...)
+}
 
-	parameters.Activations[0] = data
+// ModelOptions trains a neural network like Model with options
+func ModelOptions(ctx context.Context, data, labels mat.Dense, layers []int, learningRate float64, iterations int, options Options, callback ...Callback) (Parameters, []float64) {
+	costs := make([]float64, 0, iterations)
+	lastLayer := len(layers) - 1
+	hooks := callbacks(callback)
 
-	for i := 0; i < iterations; iterations++ {
-		PropagateForward(&parameters)
+	var parameters Parameters
+	var step func() (float64, []mat.Dense, []mat.Dense)
 
-		costs[i] = Cost(&parameters.Activations[len(layers) - 1], &labels).RawRowView(0)[0]
+	if options.BatchNorm {
+		parameters = NewNormalizedParameters(layers)
+		parameters.Activations[0] = data
+
+		step = func() (float64, []mat.Dense, []mat.Dense) {
+			PropagateForward(&parameters)
+			cost := Cost(parameters.Activations[lastLayer].T(), labels.T())
+			weightCostGradients, biasCostGradients := PropagateBackward(parameters, &labels)
+			return cost.At(0, 0), weightCostGradients, biasCostGradients
+		}
+	} else {
+		parameters = NewParameters(layers)
+
+		_, samples := data.Dims()
+		workspace := NewParallelWorkspace(layers, samples, options.Workers)
+		parameters.PreActivations, parameters.Activations = workspace.preActivations, workspace.activations
+		parameters.Activations[0] = data
+
+		step = func() (float64, []mat.Dense, []mat.Dense) {
+			workspace.Forward(&parameters, &data)
+			cost := workspace.Cost(&labels)
+			weightCostGradients, biasCostGradients := workspace.Backward(&parameters, &labels)
+			return cost, weightCostGradients, biasCostGradients
+		}
+	}
 
-		weightCostGradients, biasCostGradients := PropagateBackward(parameters, &labels)
+	parameters.Training = true
+
+	progress := &Progress{Parameters: &parameters, LearningRate: learningRate}
+	hooks.OnTrainBegin(progress)
+
+	for i := 0; i < iterations && !progress.Stopped(); i++ {
+		select {
+		case <-ctx.Done():
+			parameters.Training = false
+			hooks.OnTrainEnd(progress)
+			return parameters, costs
+		default:
+		}
+
+		progress.Epoch = i
+		hooks.OnEpochBegin(progress)
+
+		cost, weightCostGradients, biasCostGradients := step()
+		costs = append(costs, cost)
 
 		parameters.GradientUpdate(weightCostGradients, biasCostGradients, learningRate)
+
+		progress.Step++
+		progress.Cost = costs[i]
+		progress.GradientNorm = gradientNorm(weightCostGradients, biasCostGradients)
+		hooks.OnBatchEnd(progress)
+
+		progress.Metrics = map[string]float64{
+			"cost":     costs[i],
+			"accuracy": Accuracy(&parameters.Activations[lastLayer], &labels, Threshold),
+		}
+		hooks.OnEpochEnd(progress)
 	}
 
+	parameters.Training = false
+	hooks.OnTrainEnd(progress)
+
 	return parameters, costs
-}
\ No newline at end of file
+}
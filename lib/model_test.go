@@ -0,0 +1,107 @@
+package lib
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestModel(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	data := mat.NewDense(2, 4, []float64{
+		0, 0, 1, 1,
+		0, 1, 0, 1,
+	})
+
+	labels := mat.NewDense(1, 4, []float64{
+		0, 1, 1, 1,
+	})
+
+	_, costs := Model(context.Background(), *data, *labels, []int{2, 1}, 0.5, 100)
+
+	assert.Len(t, costs, 100)
+	assert.True(t, costs[99] < costs[0])
+}
+
+func TestModelCancelled(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	data := mat.NewDense(2, 4, []float64{
+		0, 0, 1, 1,
+		0, 1, 0, 1,
+	})
+
+	labels := mat.NewDense(1, 4, []float64{
+		0, 1, 1, 1,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	parameters, costs := Model(ctx, *data, *labels, []int{2, 1}, 0.5, 100)
+
+	assert.Len(t, costs, 0)
+	assert.Equal(t, []int{2, 1}, parameters.Layers)
+}
+
+func TestModelParallel(t *testing.T) {
+	data := mat.NewDense(2, 8, []float64{
+		0, 0, 1, 1, 0.5, 0.2, 0.8, 0.1,
+		0, 1, 0, 1, 0.5, 0.9, 0.3, 0.4,
+	})
+
+	labels := mat.NewDense(1, 8, []float64{
+		0, 1, 1, 1, 1, 1, 1, 0,
+	})
+
+	rand.Seed(1) // testable / static results
+	expected, expectedCosts := Model(context.Background(), *data, *labels, []int{2, 3, 1}, 0.5, 50)
+
+	rand.Seed(1) // same initial parameters
+	parameters, costs := ModelParallel(context.Background(), *data, *labels, []int{2, 3, 1}, 0.5, 50, 3)
+
+	assert.InDeltaSlice(t, expectedCosts, costs, 1e-9)
+	for layer := 1; layer < 3; layer++ {
+		assert.True(t, mat.EqualApprox(&expected.Weights[layer], &parameters.Weights[layer], 1e-9))
+		assert.True(t, mat.EqualApprox(&expected.Bias[layer], &parameters.Bias[layer], 1e-9))
+	}
+}
+
+func TestModelOptionsBatchNorm(t *testing.T) {
+	rand.Seed(1) // testable / static results
+
+	data := mat.NewDense(2, 8, []float64{
+		0, 0, 1, 1, 0.5, 0.2, 0.8, 0.1,
+		0, 1, 0, 1, 0.5, 0.9, 0.3, 0.4,
+	})
+
+	labels := mat.NewDense(1, 8, []float64{
+		0, 1, 1, 1, 1, 1, 1, 0,
+	})
+
+	parameters, costs := ModelOptions(context.Background(), *data, *labels, []int{2, 4, 3, 1}, 0.5, 200, Options{Workers: 4, BatchNorm: true})
+
+	assert.Len(t, costs, 200)
+	assert.True(t, costs[199] < costs[0])
+	assert.True(t, parameters.Normalized())
+	assert.False(t, parameters.Training)
+	assert.NotEqual(t, 0.0, parameters.BatchNorms[1].RunningMean.At(0, 0))
+}
+
+// BenchmarkEpoch of training, a forward and backward pass of a full batch updating parameters
+func BenchmarkEpoch(b *testing.B) {
+	benchmark(b, func(b *testing.B, parameters Parameters, data, labels mat.Dense) {
+		_, samples := data.Dims()
+		workspace := NewWorkspace(parameters.Layers, samples)
+		for i := 0; i < b.N; i++ {
+			workspace.Forward(&parameters, &data)
+			workspace.Cost(&labels)
+			weightCostGradients, biasCostGradients := workspace.Backward(&parameters, &labels)
+			parameters.GradientUpdate(weightCostGradients, biasCostGradients, 0.01)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package lib
 
 import (
+	"fmt"
+	"math"
+
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -16,17 +19,188 @@ type Parameters struct {
 	PreActivations []mat.Dense
 	// Activations for each layer neuron
 	Activations []mat.Dense
+	// BatchNorms applied to pre-activations of each layer, empty or nil for layers without
+	BatchNorms []*BatchNorm
+	// Training normalizes batch normalized layers with batch statistics and updates running statistics
+	Training bool
 }
 
-// GradientUpdate computes and updates weights and bias using gradient costs and learning rate
+// GradientUpdate computes and updates weights and bias in place using gradient costs and learning rate
 func (p *Parameters) GradientUpdate(weightCostGradients, biasCostGradients []mat.Dense, learningRate float64) {
 	for layer := 1; layer < len(p.Layers); layer++ {
-		weightsScaled := multiply(&weightCostGradients[layer], learningRate)
-		p.Weights[layer].Sub(&p.Weights[layer], &weightsScaled)
+		descend(&p.Weights[layer], &weightCostGradients[layer], learningRate)
+		descend(&p.Bias[layer], &biasCostGradients[layer], learningRate)
+
+		if batchNorm := p.batchNorm(layer); batchNorm != nil {
+			update(batchNorm, learningRate)
+		}
+	}
+}
+
+// descend subtracts gradients scaled by learning rate from matrix in place
+func descend(matrix, gradients *mat.Dense, learningRate float64) {
+	rows, _ := matrix.Dims()
+	for i := 0; i < rows; i++ {
+		row, gradientRow := matrix.RawRowView(i), gradients.RawRowView(i)
+		for j, gradient := range gradientRow {
+			row[j] -= gradient * learningRate
+		}
+	}
+}
+
+// Activation function name of layer neurons, relu for hidden layers and sigmoid for the output layer
+func (p *Parameters) Activation(layer int) string {
+	return activation(len(p.Layers), layer)
+}
+
+// activation function name of layer in a network of layers
+func activation(layers, layer int) string {
+	switch layer {
+	case 0:
+		return ""
+	case layers - 1:
+		return "sigmoid"
+	default:
+		return "relu"
+	}
+}
+
+// Normalized reports whether any layer applies batch normalization
+func (p *Parameters) Normalized() bool {
+	for layer := range p.BatchNorms {
+		if p.BatchNorms[layer] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Fold batch normalization into weights and bias returning parameters computing the same predictions
+// without it, for inference where normalization uses running statistics
+func (p *Parameters) Fold() Parameters {
+	if !p.Normalized() {
+		return *p
+	}
+
+	layers := len(p.Layers)
+	folded := Parameters{
+		Layers:         p.Layers,
+		Weights:        make([]mat.Dense, layers),
+		Bias:           make([]mat.Dense, layers),
+		PreActivations: make([]mat.Dense, layers),
+		Activations:    make([]mat.Dense, layers),
+	}
+
+	for layer := 1; layer < layers; layer++ {
+		folded.Weights[layer] = *mat.DenseCopyOf(&p.Weights[layer])
+		folded.Bias[layer] = *mat.DenseCopyOf(&p.Bias[layer])
+
+		batchNorm := p.batchNorm(layer)
+		if batchNorm == nil {
+			continue
+		}
+
+		for i := 0; i < p.Layers[layer]; i++ {
+			scale, shift := batchNorm.fold(i)
+			row := folded.Weights[layer].RawRowView(i)
+			for j := range row {
+				row[j] *= scale
+			}
+			folded.Bias[layer].Set(i, 0, scale*folded.Bias[layer].At(i, 0)+shift)
+		}
+	}
+
+	return folded
+}
+
+// Sequential network of layers sharing weights, bias and batch normalization with parameters
+func (p *Parameters) Sequential() *Sequential {
+	network := NewSequential()
+	for layer := 1; layer < len(p.Layers); layer++ {
+		_, linear, activation := p.layer(layer)
+		network.Layers = append(network.Layers, linear.Layers...)
+		network.Layers = append(network.Layers, activation)
+	}
+	return network
+}
+
+// layer of parameters as its dense layer, the layers computing its pre-activations (dense first) and its
+// activation, holding the layer's inputs and pre-activations so Backward may follow PropagateForward
+func (p *Parameters) layer(layer int) (*Dense, *Sequential, *Activation) {
+	dense := &Dense{Weights: &p.Weights[layer], Bias: &p.Bias[layer]}
+	activation := &Activation{Name: p.Activation(layer)}
 
-		biasScaled := multiply(&biasCostGradients[layer], learningRate)
-		p.Bias[layer].Sub(&p.Bias[layer], &biasScaled)
+	if layer < len(p.Activations) {
+		dense.inputs = p.Activations[layer-1]
+		activation.preActivations = p.PreActivations[layer]
 	}
+
+	linear := NewSequential(dense)
+	if batchNorm := p.batchNorm(layer); batchNorm != nil {
+		linear.Layers = append(linear.Layers, batchNorm)
+	}
+
+	return dense, linear, activation
+}
+
+// batchNorm of layer, nil when layer is not normalized
+func (p *Parameters) batchNorm(layer int) *BatchNorm {
+	if layer < len(p.BatchNorms) {
+		return p.BatchNorms[layer]
+	}
+	return nil
+}
+
+// Validate weight and bias shapes match layers, features (when positive) and a single output neuron
+func (p *Parameters) Validate(features int) error {
+	layers := len(p.Layers)
+	if layers < 2 {
+		return fmt.Errorf("parameters require at least 2 layers, got %d", layers)
+	}
+
+	if features > 0 && p.Layers[0] != features {
+		return fmt.Errorf("parameters input layer has %d features, expected %d", p.Layers[0], features)
+	}
+
+	if p.Layers[layers-1] != 1 {
+		return fmt.Errorf("parameters output layer has %d neurons, expected 1", p.Layers[layers-1])
+	}
+
+	if len(p.Weights) != layers || len(p.Bias) != layers {
+		return fmt.Errorf("parameters have %d weights and %d bias for %d layers", len(p.Weights), len(p.Bias), layers)
+	}
+
+	for layer := 1; layer < layers; layer++ {
+		nodes, previousNodes := p.Layers[layer], p.Layers[layer-1]
+
+		if rows, cols := p.Weights[layer].Dims(); rows != nodes || cols != previousNodes {
+			return fmt.Errorf("parameters layer %d weights are %d x %d, expected %d x %d", layer, rows, cols, nodes, previousNodes)
+		}
+
+		if rows, cols := p.Bias[layer].Dims(); rows != nodes || cols != 1 {
+			return fmt.Errorf("parameters layer %d bias is %d x %d, expected %d x 1", layer, rows, cols, nodes)
+		}
+
+		if batchNorm := p.batchNorm(layer); batchNorm != nil {
+			for _, vector := range []*mat.Dense{&batchNorm.Gamma, &batchNorm.Beta, &batchNorm.RunningMean, &batchNorm.RunningVariance} {
+				if rows, cols := vector.Dims(); rows != nodes || cols != 1 {
+					return fmt.Errorf("parameters layer %d batch normalization is %d x %d, expected %d x 1", layer, rows, cols, nodes)
+				}
+			}
+
+			for i := 0; i < nodes; i++ {
+				if variance := batchNorm.RunningVariance.At(i, 0); variance < 0 || math.IsNaN(variance) {
+					return fmt.Errorf("parameters layer %d running variance %g is invalid", layer, variance)
+				}
+			}
+		}
+	}
+
+	if len(p.BatchNorms) > layers {
+		return fmt.Errorf("parameters have %d batch normalizations for %d layers", len(p.BatchNorms), layers)
+	}
+
+	return nil
 }
 
 // NewParameters struct with initialized values
@@ -52,3 +226,15 @@ func NewParameters(layers []int) Parameters {
 
 	return parameters
 }
+
+// NewNormalizedParameters struct with initialized values and batch normalization of hidden layers
+func NewNormalizedParameters(layers []int) Parameters {
+	parameters := NewParameters(layers)
+	parameters.BatchNorms = make([]*BatchNorm, len(layers))
+
+	for i := 1; i < len(layers)-1; i++ {
+		parameters.BatchNorms[i] = NewBatchNorm(layers[i])
+	}
+
+	return parameters
+}
@@ -222,3 +222,25 @@ func TestGradientUpdate(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate(t *testing.T) {
+	parameters := NewParameters([]int{4, 3, 1})
+
+	assert.NoError(t, parameters.Validate(4))
+	assert.NoError(t, parameters.Validate(0))
+	assert.EqualError(t, parameters.Validate(5), "parameters input layer has 4 features, expected 5")
+
+	parameters.Weights[2] = *mat.NewDense(1, 2, nil)
+	assert.EqualError(t, parameters.Validate(4), "parameters layer 2 weights are 1 x 2, expected 1 x 3")
+
+	parameters = NewParameters([]int{4, 2})
+	assert.EqualError(t, parameters.Validate(4), "parameters output layer has 2 neurons, expected 1")
+
+	parameters = Parameters{Layers: []int{4}}
+	assert.EqualError(t, parameters.Validate(4), "parameters require at least 2 layers, got 1")
+
+	parameters = NewNormalizedParameters([]int{4, 3, 1})
+	assert.NoError(t, parameters.Validate(4))
+	parameters.BatchNorms[1].Beta = *mat.NewDense(2, 1, nil)
+	assert.EqualError(t, parameters.Validate(4), "parameters layer 1 batch normalization is 2 x 1, expected 3 x 1")
+}
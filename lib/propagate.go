@@ -17,42 +17,21 @@ func linearForward(previousActivations, weights, bias mat.Matrix) mat.Dense {
 	return preActivationsBiased
 }
 
-func activateForward(previousActivations, weights, bias mat.Matrix, activation string) (mat.Dense, mat.Dense) {
-	var activations mat.Dense
-
-	preActivations := linearForward(previousActivations, weights, bias)
-
-	if activation == "relu" {
-		activations = activate(&preActivations, relu)
-	}
-
-	if activation == "sigmoid" {
-		activations = activate(&preActivations, sigmoid)
-	}
-
-	return preActivations, activations
-}
-
 // PropagateForward computes neuron activations for each network layer
+//
+// Each layer is computed by its Dense, BatchNorm (when normalized) and Activation layers. Batch normalized
+// layers normalize with running statistics unless parameters are Training, which PropagateBackward
+// requires of the preceding forward pass.
 func PropagateForward(parameters *Parameters) {
 	layers := len(parameters.Layers)
 	lastLayer := layers - 1
 
 	for layer := 1; layer <= lastLayer; layer++ {
 		previousLayer := layer - 1
-		var activation string
-		if layer == lastLayer {
-			activation = "sigmoid"
-		} else {
-			activation = "relu"
-		}
-
-		parameters.PreActivations[layer], parameters.Activations[layer] = activateForward(
-			&parameters.Activations[previousLayer],
-			&parameters.Weights[layer],
-			&parameters.Bias[layer],
-			activation,
-		)
+		_, linear, activation := parameters.layer(layer)
+
+		parameters.PreActivations[layer] = linear.Forward(&parameters.Activations[previousLayer], parameters.Training)
+		parameters.Activations[layer] = activation.Forward(&parameters.PreActivations[layer], parameters.Training)
 	}
 }
 
@@ -72,21 +51,9 @@ func linearBackward(preActivationCostGradients, preActivations, weights, bias ma
 	return previousActivationCostGradients, weightCostGradients, biasCostGradients
 }
 
-func activateBackward(activationCostGradients, preActivations, previousActivations, weights, bias mat.Matrix, activation string) (mat.Dense, mat.Dense, mat.Dense) {
-	var preActivationCostGradients mat.Dense
-
-	if activation == "relu" {
-		preActivationCostGradients = activatePrime(activationCostGradients, preActivations, reluPrime)
-	}
-
-	if activation == "sigmoid" {
-		preActivationCostGradients = activatePrime(activationCostGradients, preActivations, sigmoidPrime)
-	}
-
-	return linearBackward(&preActivationCostGradients, previousActivations, weights, bias)
-}
-
 // PropagateBackward computes gradient of loss with respect to parameters for each layer in network
+//
+// Gradients of batch normalization gamma and beta are kept by each BatchNorm for GradientUpdate.
 func PropagateBackward(parameters Parameters, labels mat.Matrix) ([]mat.Dense, []mat.Dense) {
 	layers := len(parameters.Layers)
 	lastLayer := layers - 1
@@ -108,23 +75,27 @@ func PropagateBackward(parameters Parameters, labels mat.Matrix) ([]mat.Dense, [
 	activationCostGradients[lastLayer] = multiply(&activationCostGradients[lastLayer], -1)
 
 	for layer := lastLayer; layer > 0; layer-- {
-		var activation string
-		if layer == lastLayer {
-			activation = "sigmoid"
-		} else {
-			activation = "relu"
-		}
-
 		previousLayer := layer - 1 // layer or nodes to left
-		activationCostGradients[previousLayer], weightCostGradients[layer], biasCostGradients[layer] = activateBackward(
-			&activationCostGradients[layer],
-			&parameters.PreActivations[layer],
-			&parameters.Activations[previousLayer],
-			&parameters.Weights[layer],
-			&parameters.Bias[layer],
-			activation,
-		)
+		dense, linear, activation := parameters.layer(layer)
+
+		preActivationCostGradients := activation.Backward(&activationCostGradients[layer])
+		activationCostGradients[previousLayer] = linear.Backward(&preActivationCostGradients)
+		weightCostGradients[layer], biasCostGradients[layer] = dense.weightCostGradients, dense.biasCostGradients
 	}
 
 	return weightCostGradients, biasCostGradients
 }
+
+// Predict computes output layer activations for data without modifying parameters (safe for concurrent use)
+func Predict(parameters Parameters, data mat.Dense) mat.Dense {
+	layers := len(parameters.Layers)
+
+	parameters.PreActivations = make([]mat.Dense, layers)
+	parameters.Activations = make([]mat.Dense, layers)
+	parameters.Activations[0] = data
+	parameters.Training = false
+
+	PropagateForward(&parameters)
+
+	return parameters.Activations[layers-1]
+}
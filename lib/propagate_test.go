@@ -1,6 +1,9 @@
 package lib
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -41,98 +44,6 @@ func TestLinearForward(t *testing.T) {
 	}
 }
 
-func TestActivateForwardSigmoid(t *testing.T) {
-	expected := map[string][][]float64{
-		"preActivations": {
-			{
-				3.4389613356978117,
-				-2.0893843586662904,
-			},
-		},
-		"activations": {
-			{
-				0.9689002334527027,
-				0.11013289497444277,
-			},
-		},
-	}
-
-	previousActivations := mat.NewDense(3, 2, []float64{
-		-0.41675785,
-		-0.05626683,
-		-2.1361961,
-		1.64027081,
-		-1.79343559,
-		-0.84174737,
-	})
-
-	weights := mat.NewDense(1, 3, []float64{
-		0.50288142,
-		-1.24528809,
-		-1.05795222,
-	})
-
-	bias := mat.NewDense(1, 1, []float64{
-		-0.90900761,
-	})
-
-	preActivations, activations := activateForward(previousActivations, weights, bias, "sigmoid")
-
-	for i := 0; i < len(expected["preActivations"]); i++ {
-		assert.Equal(t, expected["preActivations"][i], preActivations.RawRowView(i))
-	}
-
-	for i := 0; i < len(expected["activations"]); i++ {
-		assert.Equal(t, expected["activations"][i], activations.RawRowView(i))
-	}
-}
-
-func TestActivateForwardRelu(t *testing.T) {
-	expected := map[string][][]float64{
-		"preActivations": {
-			{
-				3.4389613356978117,
-				-2.0893843586662904,
-			},
-		},
-		"activations": {
-			{
-				3.4389613356978117,
-				0,
-			},
-		},
-	}
-
-	previousActivations := mat.NewDense(3, 2, []float64{
-		-0.41675785,
-		-0.05626683,
-		-2.1361961,
-		1.64027081,
-		-1.79343559,
-		-0.84174737,
-	})
-
-	weights := mat.NewDense(1, 3, []float64{
-		0.50288142,
-		-1.24528809,
-		-1.05795222,
-	})
-
-	bias := mat.NewDense(1, 1, []float64{
-		-0.90900761,
-	})
-
-	preActivations, activations := activateForward(previousActivations, weights, bias, "relu")
-
-	for i := 0; i < len(expected["preActivations"]); i++ {
-		assert.Equal(t, expected["preActivations"][i], preActivations.RawRowView(i))
-	}
-
-	for i := 0; i < len(expected["activations"]); i++ {
-		assert.Equal(t, expected["activations"][i], activations.RawRowView(i))
-	}
-}
-
 func TestPropagateForward(t *testing.T) {
 	expected := []map[string][][]float64{
 		{
@@ -467,168 +378,6 @@ func TestLinearBackward(t *testing.T) {
 	}
 }
 
-func TestActivateBackwardRelu(t *testing.T) {
-	expected := map[string][][]float64{
-		"previousActivationCostGradients": {
-			{
-				0.44090989260992697,
-				0,
-			},
-			{
-				0.37883605717723845,
-				0,
-			},
-			{
-				-0.229822800084214,
-				0,
-			},
-		},
-		"weightCostGradients": {
-			{
-				0.44513824690719245,
-				0.3737141803009408,
-				-0.10478988970207351,
-			},
-		},
-		"biasCostGradients": {
-			{
-				-0.208378925,
-			},
-		},
-	}
-
-	activationCostGradients := mat.NewDense(1, 2, []float64{
-		-0.41675785,
-		-0.05626683,
-	})
-
-	preActivations := mat.NewDense(1, 2, []float64{
-		0.04153939,
-		-1.11792545,
-	})
-
-	previousActivations := mat.NewDense(3,2, []float64{
-		-2.1361961,
-		1.64027081,
-		-1.79343559,
-		-0.84174737,
-		0.50288142,
-		-1.24528809,
-	})
-
-	weights := mat.NewDense(1, 3, []float64{
-		-1.05795222,
-		-0.90900761,
-		0.55145404,
-	})
-
-	bias := mat.NewDense(1, 1, []float64{
-		2.29220801,
-	})
-
-	previousActivationCostGradients, weightCostGradients, biasCostGradients := activateBackward(
-		activationCostGradients,
-		preActivations,
-		previousActivations,
-		weights,
-		bias,
-		"relu",
-	)
-
-	for i := 0; i < len(expected["previousActivationCostGradients"]); i++ {
-		assert.Equal(t, expected["previousActivationCostGradients"][i], previousActivationCostGradients.RawRowView(i))
-	}
-
-	for i := 0; i < len(expected["weightCostGradients"]); i++ {
-		assert.Equal(t, expected["weightCostGradients"][i], weightCostGradients.RawRowView(i))
-	}
-
-	for i := 0; i < len(expected["biasCostGradients"]); i++ {
-		assert.Equal(t, expected["biasCostGradients"][i], biasCostGradients.RawRowView(i))
-	}
-}
-
-func TestActivateBackwardSigmoid(t *testing.T) {
-	expected := map[string][][]float64{
-		"previousActivationCostGradients": {
-			{
-				0.11017993687111528,
-				0.01105339523719341,
-			},
-			{
-				0.09466817044456259,
-				0.009497234560315553,
-			},
-			{
-				-0.057430921894111135,
-				-0.005761545128443573,
-			},
-		},
-		"weightCostGradients": {
-			{
-				0.10266786428377704,
-				0.09778550606902146,
-				-0.019680842328738218,
-			},
-		},
-		"biasCostGradients": {
-			{
-				-0.05729622274827718,
-			},
-		},
-	}
-
-	activationCostGradients := mat.NewDense(1, 2, []float64{
-		-0.41675785,
-		-0.05626683,
-	})
-
-	preActivations := mat.NewDense(1, 2, []float64{
-		0.04153939,
-		-1.11792545,
-	})
-
-	previousActivations := mat.NewDense(3,2, []float64{
-		-2.1361961,
-		1.64027081,
-		-1.79343559,
-		-0.84174737,
-		0.50288142,
-		-1.24528809,
-	})
-
-	weights := mat.NewDense(1, 3, []float64{
-		-1.05795222,
-		-0.90900761,
-		0.55145404,
-	})
-
-	bias := mat.NewDense(1, 1, []float64{
-		2.29220801,
-	})
-
-	previousActivationCostGradients, weightCostGradients, biasCostGradients := activateBackward(
-		activationCostGradients,
-		preActivations,
-		previousActivations,
-		weights,
-		bias,
-		"sigmoid",
-	)
-
-	for i := 0; i < len(expected["previousActivationCostGradients"]); i++ {
-		assert.Equal(t, expected["previousActivationCostGradients"][i], previousActivationCostGradients.RawRowView(i))
-	}
-
-	for i := 0; i < len(expected["weightCostGradients"]); i++ {
-		assert.Equal(t, expected["weightCostGradients"][i], weightCostGradients.RawRowView(i))
-	}
-
-	for i := 0; i < len(expected["biasCostGradients"]); i++ {
-		assert.Equal(t, expected["biasCostGradients"][i], biasCostGradients.RawRowView(i))
-	}
-}
-
 func TestPropagateBackward(t *testing.T) {
 	expected := []map[string][][]float64{
 		{
@@ -778,4 +527,56 @@ func TestPropagateBackward(t *testing.T) {
 			assert.Equal(t, gradients["biasCostGradients"][i], biasCostGradients[layer].RawRowView(i))
 		}
 	}
-}
\ No newline at end of file
+}
+
+// benchmarkLayers of networks and benchmarkBatches of samples benchmarks are run for
+var (
+	benchmarkLayers  = [][]int{{10, 8, 1}, {64, 32, 16, 1}, {256, 128, 64, 32, 1}}
+	benchmarkBatches = []int{1, 64, 1024}
+)
+
+// benchmark runs f as a sub-benchmark per network and batch with parameters propagated forward once
+func benchmark(b *testing.B, f func(b *testing.B, parameters Parameters, data, labels mat.Dense)) {
+	for _, layers := range benchmarkLayers {
+		for _, batch := range benchmarkBatches {
+			nodes := make([]string, len(layers))
+			for i, n := range layers {
+				nodes[i] = strconv.Itoa(n)
+			}
+
+			b.Run(fmt.Sprintf("layers=%s/batch=%d", strings.Join(nodes, "-"), batch), func(b *testing.B) {
+				parameters, data, labels := workspaceFixture(layers, batch)
+				parameters.Activations[0] = data
+				PropagateForward(&parameters)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				f(b, parameters, data, labels)
+			})
+		}
+	}
+}
+
+func BenchmarkLinearForward(b *testing.B) {
+	benchmark(b, func(b *testing.B, parameters Parameters, data, labels mat.Dense) {
+		for i := 0; i < b.N; i++ {
+			linearForward(&data, &parameters.Weights[1], &parameters.Bias[1])
+		}
+	})
+}
+
+func BenchmarkPropagateForward(b *testing.B) {
+	benchmark(b, func(b *testing.B, parameters Parameters, data, labels mat.Dense) {
+		for i := 0; i < b.N; i++ {
+			PropagateForward(&parameters)
+		}
+	})
+}
+
+func BenchmarkPropagateBackward(b *testing.B) {
+	benchmark(b, func(b *testing.B, parameters Parameters, data, labels mat.Dense) {
+		for i := 0; i < b.N; i++ {
+			PropagateBackward(parameters, &labels)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package lib
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// quantizedMax magnitude of symmetric int8 quantization
+const quantizedMax = 127
+
+// QuantizedParameters with int8 weights used for inference only
+//
+// A weight w is stored as round(w / scale) for the scale of its row (unit). Layer inputs are quantized with
+// a per-layer activation scale calibrated on sample data, so each unit is an int8 dot product accumulated
+// in int32 and rescaled to float64 before bias and activation are applied.
+type QuantizedParameters struct {
+	// Layers of neural network represented by number of neurons respectively
+	Layers []int
+	// Weights of each layer in row-major order, a row of inputs per unit
+	Weights [][]int8
+	// WeightScales of each layer unit
+	WeightScales [][]float64
+	// ActivationScales of each layer input
+	ActivationScales []float64
+	// Bias applied to each layer unit
+	Bias [][]float64
+}
+
+// QuantizationComparison of quantized to float predictions
+type QuantizationComparison struct {
+	Float     Evaluation `json:"float"`
+	Quantized Evaluation `json:"quantized"`
+	// MaxScoreError is the largest absolute score difference of a sample
+	MaxScoreError float64 `json:"max_score_error"`
+	// MeanScoreError is the mean absolute score difference
+	MeanScoreError float64 `json:"mean_score_error"`
+	// Agreement is the fraction of samples classified with the same label
+	Agreement float64 `json:"agreement"`
+}
+
+// Quantize parameters to int8 with activation scales calibrated on data
+//
+// Weight scales are per row (unit) when perRow is set, otherwise a single scale is shared by each layer.
+// Batch normalization is folded into weights and bias before they are quantized.
+func Quantize(parameters Parameters, data mat.Dense, perRow bool) QuantizedParameters {
+	parameters = parameters.Fold()
+	layers := len(parameters.Layers)
+
+	quantized := QuantizedParameters{
+		Layers:           parameters.Layers,
+		Weights:          make([][]int8, layers),
+		WeightScales:     make([][]float64, layers),
+		ActivationScales: make([]float64, layers),
+		Bias:             make([][]float64, layers),
+	}
+
+	parameters.PreActivations = make([]mat.Dense, layers)
+	parameters.Activations = make([]mat.Dense, layers)
+	parameters.Activations[0] = data
+	PropagateForward(&parameters)
+
+	for layer := 1; layer < layers; layer++ {
+		weights := &parameters.Weights[layer]
+		units, inputs := weights.Dims()
+
+		quantized.ActivationScales[layer] = scale(maxAbs(&parameters.Activations[layer-1]))
+		quantized.Bias[layer] = mat.Col(nil, 0, &parameters.Bias[layer])
+		quantized.Weights[layer] = make([]int8, units*inputs)
+		quantized.WeightScales[layer] = make([]float64, units)
+
+		layerScale := scale(maxAbs(weights))
+		for i := 0; i < units; i++ {
+			row := weights.RawRowView(i)
+
+			rowScale := layerScale
+			if perRow {
+				rowScale = scale(maxAbs(mat.NewDense(1, inputs, row)))
+			}
+			quantized.WeightScales[layer][i] = rowScale
+
+			for j, w := range row {
+				quantized.Weights[layer][i*inputs+j] = quantize(w, rowScale)
+			}
+		}
+	}
+
+	return quantized
+}
+
+// Activation function name of layer
+func (q *QuantizedParameters) Activation(layer int) string {
+	return activation(len(q.Layers), layer)
+}
+
+// Validate weight, scale and bias lengths match layers, features (when positive) and a single output neuron
+func (q *QuantizedParameters) Validate(features int) error {
+	layers := len(q.Layers)
+	if layers < 2 {
+		return fmt.Errorf("quantized parameters require at least 2 layers, got %d", layers)
+	}
+
+	if features > 0 && q.Layers[0] != features {
+		return fmt.Errorf("quantized parameters input layer has %d features, expected %d", q.Layers[0], features)
+	}
+
+	if q.Layers[layers-1] != 1 {
+		return fmt.Errorf("quantized parameters output layer has %d neurons, expected 1", q.Layers[layers-1])
+	}
+
+	if len(q.Weights) != layers || len(q.WeightScales) != layers || len(q.ActivationScales) != layers || len(q.Bias) != layers {
+		return fmt.Errorf("quantized parameters are missing layers of %d", layers)
+	}
+
+	for layer := 1; layer < layers; layer++ {
+		units, inputs := q.Layers[layer], q.Layers[layer-1]
+		if len(q.Weights[layer]) != units*inputs || len(q.WeightScales[layer]) != units || len(q.Bias[layer]) != units {
+			return fmt.Errorf("quantized parameters layer %d does not match %d x %d", layer, units, inputs)
+		}
+	}
+
+	return nil
+}
+
+// Predict computes output layer activations for data, a column per sample, using int8 arithmetic
+func (q *QuantizedParameters) Predict(data mat.Dense) mat.Dense {
+	layers := len(q.Layers)
+	_, samples := data.Dims()
+
+	activations := data
+	for layer := 1; layer < layers; layer++ {
+		units, inputs := q.Layers[layer], q.Layers[layer-1]
+		activationScale := q.ActivationScales[layer]
+
+		input := make([]int8, inputs)
+		output := mat.NewDense(units, samples, nil)
+
+		for sample := 0; sample < samples; sample++ {
+			for j := range input {
+				input[j] = quantize(activations.At(j, sample), activationScale)
+			}
+
+			for i := 0; i < units; i++ {
+				var accumulator int32
+				for j, w := range q.Weights[layer][i*inputs : (i+1)*inputs] {
+					accumulator += int32(w) * int32(input[j])
+				}
+
+				z := float64(accumulator)*q.WeightScales[layer][i]*activationScale + q.Bias[layer][i]
+				if activation(layers, layer) == "sigmoid" {
+					output.Set(i, sample, sigmoid(z))
+				} else {
+					output.Set(i, sample, relu(z))
+				}
+			}
+		}
+
+		activations = *output
+	}
+
+	return activations
+}
+
+// CompareQuantized evaluates float and quantized predictions of data against labels
+func CompareQuantized(parameters Parameters, quantized QuantizedParameters, data, labels mat.Dense) QuantizationComparison {
+	floatPredictions := Predict(parameters, data)
+	quantizedPredictions := quantized.Predict(data)
+
+	comparison := QuantizationComparison{
+		Float:     Evaluate(&floatPredictions, &labels, Threshold),
+		Quantized: Evaluate(&quantizedPredictions, &labels, Threshold),
+	}
+
+	_, samples := floatPredictions.Dims()
+	var agree int
+	for j := 0; j < samples; j++ {
+		f, q := floatPredictions.At(0, j), quantizedPredictions.At(0, j)
+
+		e := math.Abs(f - q)
+		comparison.MaxScoreError = math.Max(comparison.MaxScoreError, e)
+		comparison.MeanScoreError += e / float64(samples)
+
+		if classify(f, Threshold) == classify(q, Threshold) {
+			agree++
+		}
+	}
+	comparison.Agreement = float64(agree) / float64(samples)
+
+	return comparison
+}
+
+// scale mapping magnitude to the int8 range, 1 when magnitude is 0 so zeros quantize to 0
+func scale(magnitude float64) float64 {
+	if magnitude == 0 {
+		return 1
+	}
+	return magnitude / quantizedMax
+}
+
+func quantize(value, scale float64) int8 {
+	return int8(math.Max(-quantizedMax, math.Min(quantizedMax, math.Round(value/scale))))
+}
+
+func maxAbs(matrix mat.Matrix) float64 {
+	rows, cols := matrix.Dims()
+
+	var res float64
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			res = math.Max(res, math.Abs(matrix.At(i, j)))
+		}
+	}
+
+	return res
+}
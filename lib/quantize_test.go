@@ -0,0 +1,63 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestQuantize(t *testing.T) {
+	parameters := NewParameters([]int{2, 2, 1})
+	parameters.Weights[1] = *mat.NewDense(2, 2, []float64{1.27, -0.635, 0.254, 0.127})
+	parameters.Bias[1] = *mat.NewDense(2, 1, []float64{0.5, -0.5})
+	parameters.Weights[2] = *mat.NewDense(1, 2, []float64{2.54, 0})
+	parameters.Bias[2] = *mat.NewDense(1, 1, []float64{0.25})
+
+	data := *mat.NewDense(2, 2, []float64{1, -2, 0.5, 0})
+
+	quantized := Quantize(parameters, data, true)
+
+	assert.Equal(t, []int8{127, -64, 127, 64}, quantized.Weights[1])
+	assert.InDeltaSlice(t, []float64{0.01, 0.002}, quantized.WeightScales[1], 1e-12)
+	assert.InDelta(t, 2.0/127, quantized.ActivationScales[1], 1e-12)
+	assert.Equal(t, []float64{0.5, -0.5}, quantized.Bias[1])
+
+	layerScaled := Quantize(parameters, data, false)
+
+	assert.Equal(t, []int8{127, -64, 25, 13}, layerScaled.Weights[1])
+	assert.InDeltaSlice(t, []float64{0.01, 0.01}, layerScaled.WeightScales[1], 1e-12)
+}
+
+func TestQuantizedPredict(t *testing.T) {
+	parameters := NewParameters([]int{3, 4, 1})
+	parameters.Weights[1] = *mat.NewDense(4, 3, []float64{
+		0.8, -0.4, 0.3,
+		-0.6, 0.9, 0.1,
+		0.2, 0.5, -0.7,
+		-0.3, -0.2, 0.6,
+	})
+	parameters.Bias[1] = *mat.NewDense(4, 1, []float64{0.1, -0.2, 0.05, 0})
+	parameters.Weights[2] = *mat.NewDense(1, 4, []float64{1.5, -1.2, 0.8, -0.5})
+	parameters.Bias[2] = *mat.NewDense(1, 1, []float64{-0.1})
+	data := *mat.NewDense(3, 4, []float64{
+		1, -1, 0.5, 0.25,
+		0.1, 0.2, -0.3, 0.9,
+		-0.7, 0.4, 0.6, -0.2,
+	})
+	labels := *mat.NewDense(1, 4, []float64{1, 0, 1, 0})
+
+	quantized := Quantize(parameters, data, true)
+
+	expected := Predict(parameters, data)
+	actual := quantized.Predict(data)
+
+	assert.True(t, mat.EqualApprox(&expected, &actual, 1e-2))
+
+	comparison := CompareQuantized(parameters, quantized, data, labels)
+
+	assert.InDelta(t, 0, comparison.MaxScoreError, 1e-2)
+	assert.True(t, comparison.MeanScoreError <= comparison.MaxScoreError)
+	assert.Equal(t, 1.0, comparison.Agreement)
+	assert.Equal(t, comparison.Float.Accuracy, comparison.Quantized.Accuracy)
+}
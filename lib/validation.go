@@ -0,0 +1,32 @@
+package lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Validation callback computing cost and accuracy of held out data at the end of each epoch
+//
+// Metrics are added to the progress as "val_cost" and "val_accuracy" so callbacks registered after it can
+// report them.
+type Validation struct {
+	BaseCallback
+	data   mat.Dense
+	labels mat.Dense
+}
+
+// OnEpochEnd adds validation metrics to progress
+func (v *Validation) OnEpochEnd(progress *Progress) {
+	predictions := Predict(*progress.Parameters, v.data)
+	cost := Cost(predictions.T(), v.labels.T())
+
+	progress.Metrics["val_cost"] = cost.At(0, 0)
+	progress.Metrics["val_accuracy"] = Accuracy(&predictions, &v.labels, Threshold)
+}
+
+// ObservesParameters as validation predicts with them each epoch
+func (v *Validation) ObservesParameters() {}
+
+// NewValidation callback for data with a column per sample and labels as a row
+func NewValidation(data, labels mat.Dense) *Validation {
+	return &Validation{data: data, labels: labels}
+}
@@ -0,0 +1,30 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestValidation(t *testing.T) {
+	parameters := NewParameters([]int{2, 1})
+	parameters.Weights[1] = *mat.NewDense(1, 2, []float64{1, 1})
+	parameters.Bias[1] = *mat.NewDense(1, 1, []float64{-1})
+
+	data := mat.NewDense(2, 2, []float64{
+		0, 2,
+		0, 2,
+	})
+
+	labels := mat.NewDense(1, 2, []float64{
+		0, 1,
+	})
+
+	progress := &Progress{Parameters: &parameters, Metrics: map[string]float64{}}
+
+	NewValidation(*data, *labels).OnEpochEnd(progress)
+
+	assert.Equal(t, 1.0, progress.Metrics["val_accuracy"])
+	assert.InDelta(t, 0.1809, progress.Metrics["val_cost"], 1e-4)
+}
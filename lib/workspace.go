@@ -0,0 +1,270 @@
+package lib
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Workspace of buffers reused by forward and backward passes of a network for a fixed number of samples
+//
+// Buffers are allocated once by NewWorkspace and written in place on every pass, so a training step does
+// not allocate. Matrices returned by a workspace are overwritten by its next pass. Dense layers and their
+// relu or sigmoid activations are fused into loops rather than passed as Layer implementations, so batch
+// normalization is not applied by workspaces, Model passes batch normalized parameters with PropagateForward
+// instead.
+//
+// A parallel workspace splits samples into shards passed concurrently, each shard writing activations into
+// its columns of the workspace buffers and computing gradients of its samples that are reduced afterwards.
+type Workspace struct {
+	layers  []int
+	samples int
+	// offset of shard samples within parent workspace samples
+	offset int
+	// labels of shard samples
+	labels mat.Dense
+	// shards passed concurrently, empty when passed by a single goroutine
+	shards []*Workspace
+	// PreActivations of each layer neuron per sample
+	preActivations []mat.Dense
+	// Activations of each layer neuron per sample, the input layer references pass data
+	activations []mat.Dense
+	// gradients of cost with respect to each layer's activations and pre-activations
+	activationCostGradients    []mat.Dense
+	preActivationCostGradients []mat.Dense
+	// gradients of cost with respect to parameters
+	weightCostGradients []mat.Dense
+	biasCostGradients   []mat.Dense
+}
+
+// Forward computes neuron activations for each network layer returning output layer activations
+//
+// Data must have a column per workspace sample.
+func (w *Workspace) Forward(parameters *Parameters, data *mat.Dense) *mat.Dense {
+	layers := len(w.layers)
+	w.activations[0] = *data
+
+	if len(w.shards) > 0 {
+		w.parallel(func(shard *Workspace) {
+			columns(data, shard.offset, shard.samples, &shard.activations[0])
+			shard.Forward(parameters, &shard.activations[0])
+		})
+		return &w.activations[layers-1]
+	}
+
+	for layer := 1; layer < layers; layer++ {
+		preActivations, activations := &w.preActivations[layer], &w.activations[layer]
+
+		gemm(blas.NoTrans, blas.NoTrans, 1, &parameters.Weights[layer], &w.activations[layer-1], preActivations)
+
+		z, a := preActivations.RawMatrix(), activations.RawMatrix()
+		name := activation(layers, layer)
+		for i := 0; i < z.Rows; i++ {
+			bias := parameters.Bias[layer].At(i, 0)
+			zRow, aRow := z.Data[i*z.Stride:i*z.Stride+z.Cols], a.Data[i*a.Stride:i*a.Stride+a.Cols]
+			for j := range zRow {
+				zRow[j] += bias
+				if name == "sigmoid" {
+					aRow[j] = sigmoid(zRow[j])
+				} else {
+					aRow[j] = relu(zRow[j])
+				}
+			}
+		}
+	}
+
+	return &w.activations[layers-1]
+}
+
+// Backward computes gradients of cost with respect to weights and bias of each layer from the last Forward
+func (w *Workspace) Backward(parameters *Parameters, labels *mat.Dense) ([]mat.Dense, []mat.Dense) {
+	layers := len(w.layers)
+	lastLayer := layers - 1
+	samples := float64(w.samples)
+
+	if len(w.shards) > 0 {
+		w.parallel(func(shard *Workspace) {
+			columns(labels, shard.offset, shard.samples, &shard.labels)
+			shard.Backward(parameters, &shard.labels)
+		})
+		w.reduce()
+		return w.weightCostGradients, w.biasCostGradients
+	}
+
+	output, y, gradients := w.activations[lastLayer].RawMatrix(), labels.RawMatrix(), w.activationCostGradients[lastLayer].RawMatrix()
+	for j := 0; j < output.Cols; j++ {
+		a, label := output.Data[j], y.Data[j]
+		gradients.Data[j] = -(label/a - (1-label)/(1-a))
+	}
+
+	for layer := lastLayer; layer > 0; layer-- {
+		z, dA, dZ := w.preActivations[layer].RawMatrix(), w.activationCostGradients[layer].RawMatrix(), w.preActivationCostGradients[layer].RawMatrix()
+		name := activation(layers, layer)
+		for i := 0; i < z.Rows; i++ {
+			for j := 0; j < z.Cols; j++ {
+				if name == "sigmoid" {
+					dZ.Data[i*dZ.Stride+j] = sigmoidPrime(dA.Data[i*dA.Stride+j], z.Data[i*z.Stride+j])
+				} else {
+					dZ.Data[i*dZ.Stride+j] = reluPrime(dA.Data[i*dA.Stride+j], z.Data[i*z.Stride+j])
+				}
+			}
+		}
+
+		gemm(blas.NoTrans, blas.Trans, 1/samples, &w.preActivationCostGradients[layer], &w.activations[layer-1], &w.weightCostGradients[layer])
+
+		db := w.biasCostGradients[layer].RawMatrix()
+		for i := 0; i < dZ.Rows; i++ {
+			var sum float64
+			for _, value := range dZ.Data[i*dZ.Stride : i*dZ.Stride+dZ.Cols] {
+				sum += value
+			}
+			db.Data[i*db.Stride] = sum / samples
+		}
+
+		if layer > 1 {
+			gemm(blas.Trans, blas.NoTrans, 1, &parameters.Weights[layer], &w.preActivationCostGradients[layer], &w.activationCostGradients[layer-1])
+		}
+	}
+
+	return w.weightCostGradients, w.biasCostGradients
+}
+
+// Cost (cross-entropy) of the last Forward's output activations to labels
+func (w *Workspace) Cost(labels *mat.Dense) float64 {
+	output, y := w.activations[len(w.layers)-1].RawMatrix(), labels.RawMatrix()
+
+	var sum float64
+	for j := 0; j < output.Cols; j++ {
+		a, label := output.Data[j], y.Data[j]
+		sum += label*math.Log(a) + (1-label)*math.Log(1-a)
+	}
+
+	return -sum / float64(w.samples)
+}
+
+// parallel calls f with each shard concurrently returning once all calls return
+func (w *Workspace) parallel(f func(shard *Workspace)) {
+	var wg sync.WaitGroup
+	wg.Add(len(w.shards))
+	for _, shard := range w.shards {
+		go func(shard *Workspace) {
+			defer wg.Done()
+			f(shard)
+		}(shard)
+	}
+	wg.Wait()
+}
+
+// reduce shard gradients to gradients of all samples, each shard's mean weighted by its share of samples
+func (w *Workspace) reduce() {
+	for layer := 1; layer < len(w.layers); layer++ {
+		for _, gradients := range [][]mat.Dense{w.weightCostGradients, w.biasCostGradients} {
+			gradients[layer].Zero()
+		}
+
+		for _, shard := range w.shards {
+			share := float64(shard.samples) / float64(w.samples)
+			accumulate(&w.weightCostGradients[layer], &shard.weightCostGradients[layer], share)
+			accumulate(&w.biasCostGradients[layer], &shard.biasCostGradients[layer], share)
+		}
+	}
+}
+
+// accumulate adds matrix scaled by share to sum in place
+func accumulate(sum, matrix *mat.Dense, share float64) {
+	rows, _ := sum.Dims()
+	for i := 0; i < rows; i++ {
+		row, matrixRow := sum.RawRowView(i), matrix.RawRowView(i)
+		for j, value := range matrixRow {
+			row[j] += value * share
+		}
+	}
+}
+
+// columns sets view to samples columns of matrix from offset sharing its data
+func columns(matrix *mat.Dense, offset, samples int, view *mat.Dense) {
+	raw := matrix.RawMatrix()
+	view.SetRawMatrix(blas64.General{
+		Rows:   raw.Rows,
+		Cols:   samples,
+		Stride: raw.Stride,
+		Data:   raw.Data[offset : (raw.Rows-1)*raw.Stride+offset+samples],
+	})
+}
+
+// gemm computes c = alpha * op(a) * op(b) in place
+func gemm(tA, tB blas.Transpose, alpha float64, a, b, c *mat.Dense) {
+	blas64.Gemm(tA, tB, alpha, a.RawMatrix(), b.RawMatrix(), 0, c.RawMatrix())
+}
+
+// NewWorkspace for a network of layers passing samples at a time
+func NewWorkspace(layers []int, samples int) *Workspace {
+	w := newWorkspace(layers, samples)
+
+	for layer := 1; layer < len(layers); layer++ {
+		nodes, features := layers[layer], layers[layer-1]
+		w.preActivations[layer] = *mat.NewDense(nodes, samples, nil)
+		w.activations[layer] = *mat.NewDense(nodes, samples, nil)
+		w.activationCostGradients[layer] = *mat.NewDense(nodes, samples, nil)
+		w.preActivationCostGradients[layer] = *mat.NewDense(nodes, samples, nil)
+		w.weightCostGradients[layer] = *mat.NewDense(nodes, features, nil)
+		w.biasCostGradients[layer] = *mat.NewDense(nodes, 1, nil)
+	}
+
+	return w
+}
+
+// newWorkspace without buffers
+func newWorkspace(layers []int, samples int) *Workspace {
+	return &Workspace{
+		layers:                     layers,
+		samples:                    samples,
+		preActivations:             make([]mat.Dense, len(layers)),
+		activations:                make([]mat.Dense, len(layers)),
+		activationCostGradients:    make([]mat.Dense, len(layers)),
+		preActivationCostGradients: make([]mat.Dense, len(layers)),
+		weightCostGradients:        make([]mat.Dense, len(layers)),
+		biasCostGradients:          make([]mat.Dense, len(layers)),
+	}
+}
+
+// NewParallelWorkspace for a network of layers passing samples at a time split across workers goroutines
+//
+// Workers less than 1 uses a worker per CPU. Workers are limited to samples, a single worker is equivalent
+// to NewWorkspace.
+func NewParallelWorkspace(layers []int, samples, workers int) *Workspace {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if workers > samples {
+		workers = samples
+	}
+
+	w := NewWorkspace(layers, samples)
+	if workers < 2 {
+		return w
+	}
+
+	for k := 0; k < workers; k++ {
+		offset := k * samples / workers
+		shard := newWorkspace(layers, (k+1)*samples/workers-offset)
+		shard.offset = offset
+
+		for layer := 1; layer < len(layers); layer++ {
+			shard.weightCostGradients[layer] = *mat.NewDense(layers[layer], layers[layer-1], nil)
+			shard.biasCostGradients[layer] = *mat.NewDense(layers[layer], 1, nil)
+			columns(&w.preActivations[layer], offset, shard.samples, &shard.preActivations[layer])
+			columns(&w.activations[layer], offset, shard.samples, &shard.activations[layer])
+			columns(&w.activationCostGradients[layer], offset, shard.samples, &shard.activationCostGradients[layer])
+			columns(&w.preActivationCostGradients[layer], offset, shard.samples, &shard.preActivationCostGradients[layer])
+		}
+
+		w.shards = append(w.shards, shard)
+	}
+
+	return w
+}
@@ -0,0 +1,121 @@
+package lib
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func workspaceFixture(layers []int, samples int) (Parameters, mat.Dense, mat.Dense) {
+	rand.Seed(1) // testable / static results
+
+	parameters := NewParameters(layers)
+	data := *mat.NewDense(layers[0], samples, normRand(layers[0]*samples))
+
+	labels := *mat.NewDense(1, samples, nil)
+	for j := 0; j < samples; j++ {
+		labels.Set(0, j, float64(j%2))
+	}
+
+	return parameters, data, labels
+}
+
+func TestWorkspace(t *testing.T) {
+	parameters, data, labels := workspaceFixture([]int{3, 4, 2, 1}, 5)
+
+	workspace := NewWorkspace(parameters.Layers, 5)
+	output := workspace.Forward(&parameters, &data)
+	weightCostGradients, biasCostGradients := workspace.Backward(&parameters, &labels)
+
+	parameters.Activations[0] = data
+	PropagateForward(&parameters)
+	expectedWeightCostGradients, expectedBiasCostGradients := PropagateBackward(parameters, &labels)
+	expectedCost := Cost(parameters.Activations[3].T(), labels.T())
+
+	assert.True(t, mat.EqualApprox(&parameters.Activations[3], output, 1e-12))
+	assert.InDelta(t, expectedCost.At(0, 0), workspace.Cost(&labels), 1e-12)
+	for layer := 1; layer < 4; layer++ {
+		assert.True(t, mat.EqualApprox(&expectedWeightCostGradients[layer], &weightCostGradients[layer], 1e-12))
+		assert.True(t, mat.EqualApprox(&expectedBiasCostGradients[layer], &biasCostGradients[layer], 1e-12))
+	}
+}
+
+func TestParallelWorkspace(t *testing.T) {
+	parameters, data, labels := workspaceFixture([]int{3, 4, 2, 1}, 11)
+
+	workspace := NewWorkspace(parameters.Layers, 11)
+	expectedOutput := mat.DenseCopyOf(workspace.Forward(&parameters, &data))
+	expectedCost := workspace.Cost(&labels)
+	expectedWeightCostGradients, expectedBiasCostGradients := workspace.Backward(&parameters, &labels)
+
+	for _, workers := range []int{2, 3, 11, 20} {
+		parallel := NewParallelWorkspace(parameters.Layers, 11, workers)
+		output := parallel.Forward(&parameters, &data)
+		weightCostGradients, biasCostGradients := parallel.Backward(&parameters, &labels)
+
+		assert.True(t, mat.EqualApprox(expectedOutput, output, 1e-12), "workers %d", workers)
+		assert.InDelta(t, expectedCost, parallel.Cost(&labels), 1e-12, "workers %d", workers)
+		for layer := 1; layer < 4; layer++ {
+			assert.True(t, mat.EqualApprox(&expectedWeightCostGradients[layer], &weightCostGradients[layer], 1e-12), "workers %d", workers)
+			assert.True(t, mat.EqualApprox(&expectedBiasCostGradients[layer], &biasCostGradients[layer], 1e-12), "workers %d", workers)
+		}
+	}
+}
+
+func TestWorkspaceAllocations(t *testing.T) {
+	parameters, data, labels := workspaceFixture([]int{3, 4, 2, 1}, 5)
+	workspace := NewWorkspace(parameters.Layers, 5)
+
+	allocations := testing.AllocsPerRun(100, func() {
+		workspace.Forward(&parameters, &data)
+		workspace.Cost(&labels)
+		weightCostGradients, biasCostGradients := workspace.Backward(&parameters, &labels)
+		parameters.GradientUpdate(weightCostGradients, biasCostGradients, 0.1)
+	})
+
+	assert.Equal(t, float64(0), allocations)
+}
+
+func BenchmarkWorkspaceStep(b *testing.B) {
+	parameters, data, labels := workspaceFixture([]int{20, 16, 8, 1}, 256)
+	workspace := NewWorkspace(parameters.Layers, 256)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		workspace.Forward(&parameters, &data)
+		workspace.Cost(&labels)
+		weightCostGradients, biasCostGradients := workspace.Backward(&parameters, &labels)
+		parameters.GradientUpdate(weightCostGradients, biasCostGradients, 0.01)
+	}
+}
+
+func BenchmarkPropagateStep(b *testing.B) {
+	parameters, data, labels := workspaceFixture([]int{20, 16, 8, 1}, 256)
+	parameters.Activations[0] = data
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		PropagateForward(&parameters)
+		Cost(parameters.Activations[3].T(), labels.T())
+		weightCostGradients, biasCostGradients := PropagateBackward(parameters, &labels)
+		parameters.GradientUpdate(weightCostGradients, biasCostGradients, 0.01)
+	}
+}
+
+func BenchmarkParallelWorkspaceStep(b *testing.B) {
+	parameters, data, labels := workspaceFixture([]int{20, 16, 8, 1}, 4096)
+	workspace := NewParallelWorkspace(parameters.Layers, 4096, 0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		workspace.Forward(&parameters, &data)
+		workspace.Cost(&labels)
+		weightCostGradients, biasCostGradients := workspace.Backward(&parameters, &labels)
+		parameters.GradientUpdate(weightCostGradients, biasCostGradients, 0.01)
+	}
+}